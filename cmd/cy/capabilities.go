@@ -0,0 +1,158 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	P "github.com/cfoust/cy/pkg/io/protocol"
+
+	"github.com/muesli/termenv"
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// CAPABILITY_QUERY_TIMEOUT is how long the client waits for its terminal to
+// respond to the queries it uses to detect capabilities.
+const CAPABILITY_QUERY_TIMEOUT = time.Second
+
+// capabilityQueries asks the terminal whether it supports synchronized
+// output (DECRQM for mode 2026) and the kitty keyboard protocol, followed by
+// primary device attributes (DA1). Nearly every terminal responds to DA1, so
+// its response marks the end of the terminal's responses.
+const capabilityQueries = "\x1b[?2026$p\x1b[?u\x1b[c"
+
+var (
+	synchronizedResponse = regexp.MustCompile(`\x1b\[\?2026;([0-4])\$y`)
+	kittyResponse        = regexp.MustCompile(`\x1b\[\?\d*u`)
+	attributesResponse   = regexp.MustCompile(`\x1b\[\?([\d;]*)c`)
+)
+
+// queryTerminal writes capabilityQueries to the terminal and returns
+// everything it sends back until it responds to DA1 or
+// CAPABILITY_QUERY_TIMEOUT elapses.
+func queryTerminal(in, out *os.File) string {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(out.Fd())) {
+		return ""
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return ""
+	}
+	defer term.Restore(fd, oldState)
+
+	if _, err := out.WriteString(capabilityQueries); err != nil {
+		return ""
+	}
+
+	var (
+		response []byte
+		buffer   = make([]byte, 256)
+		deadline = time.Now().Add(CAPABILITY_QUERY_TIMEOUT)
+	)
+	for !attributesResponse.Match(response) {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(remaining.Milliseconds()))
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil || n == 0 {
+			break
+		}
+
+		read, err := in.Read(buffer)
+		if err != nil {
+			break
+		}
+		response = append(response, buffer[:read]...)
+	}
+
+	return string(response)
+}
+
+// parseCapabilities determines the capabilities of the terminal from its
+// responses to capabilityQueries and the client's environment. Terminals
+// cannot be asked whether they support OSC 8 hyperlinks or (in most cases)
+// OSC 52, so those are detected using the terminal's name.
+func parseCapabilities(
+	response string,
+	profile termenv.Profile,
+	env map[string]string,
+) (capabilities []P.Capability) {
+	if profile == termenv.TrueColor {
+		capabilities = append(capabilities, P.CapabilityTrueColor)
+	}
+
+	// 1 and 2 mean the mode is supported and set or reset, respectively,
+	// and 3 means it is permanently set
+	if match := synchronizedResponse.FindStringSubmatch(response); match != nil {
+		if match[1] == "1" || match[1] == "2" || match[1] == "3" {
+			capabilities = append(
+				capabilities,
+				P.CapabilitySynchronizedOutput,
+			)
+		}
+	}
+
+	if kittyResponse.MatchString(response) {
+		capabilities = append(capabilities, P.CapabilityKittyKeyboard)
+	}
+
+	program := env["TERM_PROGRAM"]
+	name := env["TERM"]
+	isModern := slices.Contains([]string{
+		"iTerm.app",
+		"WezTerm",
+		"ghostty",
+		"vscode",
+	}, program) ||
+		slices.ContainsFunc([]string{
+			"kitty",
+			"alacritty",
+			"foot",
+			"wezterm",
+			"ghostty",
+		}, func(prefix string) bool {
+			return strings.HasPrefix(name, prefix) ||
+				strings.HasPrefix(name, "xterm-"+prefix)
+		})
+
+	// Terminals that support OSC 52 may report it as attribute 52
+	var attributes []string
+	if match := attributesResponse.FindStringSubmatch(response); match != nil {
+		attributes = strings.Split(match[1], ";")
+	}
+
+	if isModern || slices.Contains(attributes, "52") {
+		capabilities = append(capabilities, P.CapabilityOSC52)
+	}
+
+	// VTE-based terminals have supported hyperlinks since 0.50
+	vte, _ := strconv.Atoi(env["VTE_VERSION"])
+	if isModern || vte >= 5000 {
+		capabilities = append(capabilities, P.CapabilityHyperlinks)
+	}
+
+	return capabilities
+}
+
+// detectCapabilities determines the capabilities of the terminal the client
+// is running in. It must be called before the client begins reading input
+// from the terminal.
+func detectCapabilities(profile termenv.Profile) []P.Capability {
+	return parseCapabilities(
+		queryTerminal(os.Stdin, os.Stdout),
+		profile,
+		getEnv(),
+	)
+}
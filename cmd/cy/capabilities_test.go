@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+
+	P "github.com/cfoust/cy/pkg/io/protocol"
+
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Response string
+		Profile  termenv.Profile
+		Env      map[string]string
+		Expected []P.Capability
+	}{
+		{
+			Name:     "no response",
+			Profile:  termenv.ANSI256,
+			Expected: nil,
+		},
+		{
+			Name:     "truecolor",
+			Profile:  termenv.TrueColor,
+			Expected: []P.Capability{P.CapabilityTrueColor},
+		},
+		{
+			Name:     "queries",
+			Response: "\x1b[?2026;2$y\x1b[?0u\x1b[?62;22;52c",
+			Profile:  termenv.ANSI256,
+			Expected: []P.Capability{
+				P.CapabilitySynchronizedOutput,
+				P.CapabilityKittyKeyboard,
+				P.CapabilityOSC52,
+			},
+		},
+		{
+			Name:     "unsupported mode",
+			Response: "\x1b[?2026;0$y\x1b[?1;2c",
+			Profile:  termenv.ANSI256,
+			Expected: nil,
+		},
+		{
+			Name:    "terminal name",
+			Profile: termenv.ANSI256,
+			Env: map[string]string{
+				"TERM": "xterm-kitty",
+			},
+			Expected: []P.Capability{
+				P.CapabilityOSC52,
+				P.CapabilityHyperlinks,
+			},
+		},
+		{
+			Name:    "vte",
+			Profile: termenv.ANSI256,
+			Env: map[string]string{
+				"VTE_VERSION": "7200",
+			},
+			Expected: []P.Capability{P.CapabilityHyperlinks},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			require.Equal(
+				t,
+				test.Expected,
+				parseCapabilities(
+					test.Response,
+					test.Profile,
+					test.Env,
+				),
+			)
+		})
+	}
+}
@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cfoust/cy/pkg/geom"
@@ -12,6 +17,7 @@ import (
 	"github.com/cfoust/cy/pkg/io/ws"
 	"github.com/cfoust/cy/pkg/mux"
 	"github.com/cfoust/cy/pkg/mux/stream/cli"
+	"github.com/cfoust/cy/pkg/util"
 
 	"github.com/muesli/termenv"
 	"golang.org/x/term"
@@ -22,17 +28,48 @@ const (
 	ECONNREFUSED = "connection refused"
 )
 
+// RECONNECT_TIMEOUT controls how long the client tries to reconnect to the
+// server after its connection drops or it is told to reconnect, such as
+// during an upgrade. The interval between attempts starts at
+// RECONNECT_MIN_INTERVAL and doubles after each attempt up to
+// RECONNECT_MAX_INTERVAL.
+const (
+	RECONNECT_TIMEOUT      = time.Minute
+	RECONNECT_MIN_INTERVAL = 100 * time.Millisecond
+	RECONNECT_MAX_INTERVAL = 5 * time.Second
+)
+
 type ClientIO struct {
+	sync.Mutex
 	conn Connection
 	r    *io.PipeReader
 }
 
+// send sends a message to the server. Messages must not be sent
+// concurrently.
+func (c *ClientIO) send(msg P.Message) error {
+	c.Lock()
+	defer c.Unlock()
+	return c.conn.Send(msg)
+}
+
+// setConn replaces the connection to the server, sending `handshake` over
+// the new connection before any other messages.
+func (c *ClientIO) setConn(conn Connection, handshake P.HandshakeMessage) error {
+	c.Lock()
+	defer c.Unlock()
+	c.conn = conn
+	return conn.Send(handshake)
+}
+
 func (c *ClientIO) Write(p []byte) (n int, err error) {
-	err = c.conn.Send(P.InputMessage{
+	// Input sent while the client is reconnecting is dropped rather than
+	// ending the session
+	_ = c.send(P.InputMessage{
 		Data: p,
 	})
 
-	return len(p), err
+	return len(p), nil
 }
 
 func (c *ClientIO) Read(p []byte) (n int, err error) {
@@ -40,7 +77,7 @@ func (c *ClientIO) Read(p []byte) (n int, err error) {
 }
 
 func (c *ClientIO) Resize(size geom.Vec2) error {
-	return c.conn.Send(P.SizeMessage{
+	return c.send(P.SizeMessage{
 		Rows:    size.R,
 		Columns: size.C,
 	})
@@ -103,7 +140,22 @@ func getEnv() map[string]string {
 	return env
 }
 
-func buildHandshake(profile termenv.Profile) (*P.HandshakeMessage, error) {
+// newToken generates the token the client uses to resume its session on the
+// server if its connection drops.
+func newToken() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(data), nil
+}
+
+func buildHandshake(
+	profile termenv.Profile,
+	token string,
+	capabilities []P.Capability,
+) (*P.HandshakeMessage, error) {
 	columns, rows, err := term.GetSize(int(os.Stdin.Fd()))
 	if err != nil {
 		return nil, err
@@ -116,55 +168,172 @@ func buildHandshake(profile termenv.Profile) (*P.HandshakeMessage, error) {
 			R: rows,
 			C: columns,
 		},
-		Profile: profile,
+		Profile:      profile,
+		ReadOnly:     CLI.Connect.ReadOnly,
+		Follow:       CLI.Connect.Follow,
+		Token:        token,
+		Version:      P.Version,
+		MinVersion:   P.MinVersion,
+		Capabilities: capabilities,
 	}, nil
 }
 
-func poll(conn Connection) error {
+// receive writes the output the server sends over `conn` to `w` until the
+// connection closes. It returns true if the server asked the client to
+// reconnect or the connection dropped, and an error if the server closed
+// the connection because of one.
+func receive(conn Connection, w io.Writer) (shouldReconnect bool, err error) {
+	events := conn.Receive()
+	for {
+		select {
+		case <-conn.Ctx().Done():
+			return true, nil
+		case packet := <-events:
+			if packet.Error != nil {
+				conn.Close()
+				return true, nil
+			}
+
+			switch msg := packet.Contents.(type) {
+			case *P.OutputMessage:
+				w.Write(msg.Data)
+			case *P.CloseMessage:
+				conn.Close()
+				return false, nil
+			case *P.ErrorMessage:
+				conn.Close()
+				return false, errors.New(msg.Message)
+			case *P.ReconnectMessage:
+				conn.Close()
+				return true, nil
+			}
+		}
+	}
+}
+
+// RECONNECT_STATUS is shown while the client is trying to reconnect.
+const RECONNECT_STATUS = "\x1b[2K\rcy: reconnecting..."
+
+// reconnect connects to the server at `socketPath`, backing off
+// exponentially until it begins to accept connections or RECONNECT_TIMEOUT
+// elapses. It writes RECONNECT_STATUS to `status` if the first attempt
+// fails and gives up early if the socket no longer exists.
+func reconnect(socketPath string, status io.Writer) (conn Connection, err error) {
+	deadline := time.Now().Add(RECONNECT_TIMEOUT)
+	interval := RECONNECT_MIN_INTERVAL
+	for attempt := 0; ; attempt++ {
+		conn, err = connect(socketPath, false)
+		if err == nil {
+			return conn, nil
+		}
+
+		// The socket is kept during an upgrade, so if it's gone, the
+		// server exited. A refused connection, on the other hand,
+		// may just mean a forwarded socket's SSH connection dropped
+		if strings.Contains(err.Error(), ENOENT) {
+			return nil, err
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return nil, err
+		}
+
+		if attempt == 0 {
+			status.Write([]byte(RECONNECT_STATUS))
+		}
+
+		time.Sleep(interval)
+		interval = min(interval*2, RECONNECT_MAX_INTERVAL)
+	}
+}
+
+func poll(socketPath string, conn Connection) error {
 	output := termenv.NewOutput(os.Stdout)
 
-	handshake, err := buildHandshake(output.Profile)
+	// The same token is sent every time the client connects so that the
+	// server can resume our session
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	capabilities := detectCapabilities(output.Profile)
+
+	handshake, err := buildHandshake(output.Profile, token, capabilities)
 	if err != nil {
 		return err
 	}
 
 	conn.Send(*handshake)
 
+	lifetime := util.NewLifetime(context.Background())
+
 	r, w := io.Pipe()
 	writer := &ClientIO{
 		conn: conn,
 		r:    r,
 	}
 
+	// The error the server closed the connection with, if any
+	var serverErr error
+
 	go func() {
-		events := conn.Receive()
+		defer lifetime.Cancel()
+
 		for {
-			select {
-			case <-conn.Ctx().Done():
+			shouldReconnect, err := receive(conn, w)
+			if err != nil {
+				serverErr = err
 				return
-			case packet := <-events:
-				if packet.Error != nil {
-					// TODO(cfoust): 12/25/23
-					return
-				}
+			}
 
-				switch msg := packet.Contents.(type) {
-				case *P.OutputMessage:
-					w.Write(msg.Data)
-				case *P.CloseMessage:
-					conn.Close()
-					return
-				}
+			if !shouldReconnect {
+				return
+			}
+
+			newConn, err := reconnect(socketPath, w)
+			if err != nil {
+				serverErr = fmt.Errorf(
+					"lost connection to server: %s",
+					err,
+				)
+				return
+			}
+			conn = newConn
+
+			handshake, err := buildHandshake(
+				output.Profile,
+				token,
+				capabilities,
+			)
+			if err != nil {
+				return
+			}
+
+			// If the client is resumed, it keeps following the
+			// client it was following before. Otherwise (such as
+			// after an upgrade) client IDs are not preserved, so
+			// that client may no longer exist
+			handshake.Follow = 0
+
+			err = writer.setConn(conn, *handshake)
+			if err != nil {
+				return
 			}
 		}
 	}()
 
-	return cli.Attach(
-		conn.Ctx(),
+	err = cli.Attach(
+		lifetime.Ctx(),
 		writer,
 		os.Stdin,
 		os.Stdout,
 	)
+	if err != nil {
+		return err
+	}
+
+	return serverErr
 }
 
 func connect(socketPath string, shouldStart bool) (Connection, error) {
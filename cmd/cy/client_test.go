@@ -0,0 +1,20 @@
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconnectExited(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "socket")
+
+	// The server exited and removed its socket
+	start := time.Now()
+	_, err := reconnect(path, io.Discard)
+	require.Error(t, err)
+	require.Less(t, time.Since(start), RECONNECT_MAX_INTERVAL)
+}
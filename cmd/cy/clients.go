@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// clientsCommand is the entrypoint for the clients command.
+func clientsCommand() error {
+	socketPath, err := getSocketPath(CLI.Socket)
+	if err != nil {
+		return err
+	}
+
+	conn, err := connect(socketPath, false)
+	if err != nil {
+		return err
+	}
+
+	response, err := RPC[RPCClientsArgs, RPCClientsResponse](
+		conn,
+		RPCClients,
+		RPCClientsArgs{},
+	)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSER\tSIZE\tPROFILE\tNODE\tCONNECTED\tSSH\tREAD-ONLY\tFOLLOWING\tSUSPENDED")
+	for _, client := range response.Clients {
+		node := "-"
+		if client.Node != nil {
+			node = fmt.Sprintf("%d", *client.Node)
+		}
+
+		following := "-"
+		if client.Following != nil {
+			following = fmt.Sprintf("%d", *client.Following)
+		}
+
+		connected := time.Unix(int64(client.Connected), 0)
+
+		fmt.Fprintf(
+			w,
+			"%d\t%s\t%dx%d\t%s\t%s\t%s\t%t\t%t\t%s\t%t\n",
+			client.ID,
+			client.User,
+			client.Size.C,
+			client.Size.R,
+			client.Profile,
+			node,
+			connected.Format(time.DateTime),
+			client.SSH,
+			client.ReadOnly,
+			following,
+			client.Suspended,
+		)
+	}
+
+	return w.Flush()
+}
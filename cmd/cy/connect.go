@@ -93,7 +93,7 @@ func connectCommand() error {
 		)
 	}
 
-	err = poll(conn)
+	err = poll(socketPath, conn)
 	if err != nil {
 		return fmt.Errorf(
 			"failed while polling: %s",
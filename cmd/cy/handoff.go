@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+)
+
+// HANDOFF_FILES_PER_MESSAGE is the number of file descriptors sent in a
+// single message, which must be below the kernel's limit (SCM_MAX_FD, 253).
+const HANDOFF_FILES_PER_MESSAGE = 250
+
+// sendHandoff sends `state` and `files` to the process on the other end of
+// `conn`. The files are sent as SCM_RIGHTS control messages, which give the
+// receiving process its own copies of their file descriptors.
+//
+// The format is a header containing the number of files and the length of
+// `state`, one single-byte message for every batch of files, and then
+// `state` itself.
+func sendHandoff(conn *net.UnixConn, state []byte, files []*os.File) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header[0:], uint32(len(files)))
+	binary.BigEndian.PutUint32(header[4:], uint32(len(state)))
+	_, err := conn.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(files); start += HANDOFF_FILES_PER_MESSAGE {
+		end := min(start+HANDOFF_FILES_PER_MESSAGE, len(files))
+
+		// (*os.File).Fd would put the files into blocking mode, which
+		// the new server would inherit
+		var fds []int
+		for _, file := range files[start:end] {
+			conn, err := file.SyscallConn()
+			if err != nil {
+				return err
+			}
+
+			err = conn.Control(func(fd uintptr) {
+				fds = append(fds, int(fd))
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		_, _, err := conn.WriteMsgUnix(
+			[]byte{0},
+			syscall.UnixRights(fds...),
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = conn.Write(state)
+	return err
+}
+
+// receiveHandoff receives the state and files sent by sendHandoff.
+func receiveHandoff(conn *net.UnixConn) (state []byte, files []*os.File, err error) {
+	header := make([]byte, 8)
+	_, err = io.ReadFull(conn, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	numFiles := int(binary.BigEndian.Uint32(header[0:]))
+	stateLength := int(binary.BigEndian.Uint32(header[4:]))
+
+	for len(files) < numFiles {
+		count := min(HANDOFF_FILES_PER_MESSAGE, numFiles-len(files))
+		oob := make([]byte, syscall.CmsgSpace(count*4))
+
+		_, oobn, _, _, err := conn.ReadMsgUnix(make([]byte, 1), oob)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		messages, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		received := 0
+		for _, message := range messages {
+			fds, err := syscall.ParseUnixRights(&message)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for _, fd := range fds {
+				files = append(
+					files,
+					os.NewFile(uintptr(fd), "handoff"),
+				)
+			}
+			received += len(fds)
+		}
+
+		if received != count {
+			return nil, nil, fmt.Errorf(
+				"expected %d files, got %d",
+				count,
+				received,
+			)
+		}
+	}
+
+	state = make([]byte, stateLength)
+	_, err = io.ReadFull(conn, state)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return state, files, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandoff(t *testing.T) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	require.NoError(t, err)
+
+	newConn := func(fd int) *net.UnixConn {
+		file := os.NewFile(uintptr(fd), "")
+		defer file.Close()
+		conn, err := net.FileConn(file)
+		require.NoError(t, err)
+		return conn.(*net.UnixConn)
+	}
+
+	a := newConn(fds[0])
+	b := newConn(fds[1])
+	defer a.Close()
+	defer b.Close()
+
+	// More than fit in a single message
+	numFiles := HANDOFF_FILES_PER_MESSAGE + 10
+	dir := t.TempDir()
+
+	var files []*os.File
+	for i := 0; i < numFiles; i++ {
+		file, err := os.Create(filepath.Join(dir, "file"))
+		require.NoError(t, err)
+		defer file.Close()
+		files = append(files, file)
+	}
+
+	_, err = files[numFiles-1].Write([]byte("test"))
+	require.NoError(t, err)
+
+	state := []byte("state")
+	errc := make(chan error)
+	go func() { errc <- sendHandoff(a, state, files) }()
+
+	received, receivedFiles, err := receiveHandoff(b)
+	require.NoError(t, err)
+	require.NoError(t, <-errc)
+	require.Equal(t, state, received)
+	require.Len(t, receivedFiles, numFiles)
+
+	last := receivedFiles[numFiles-1]
+	_, err = last.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	data, err := io.ReadAll(last)
+	require.NoError(t, err)
+	require.Equal(t, "test", string(data))
+
+	for _, file := range receivedFiles {
+		file.Close()
+	}
+}
@@ -12,7 +12,8 @@ import (
 )
 
 var CLI struct {
-	Socket string `help:"Specify the name of the socket." name:"socket-name" optional:"" short:"L" default:"default"`
+	Socket     string `help:"Specify the name of the socket." name:"socket-name" optional:"" short:"L" default:"default"`
+	SocketPath string `help:"Specify the full path to the socket, such as one shared by another user. Overrides --socket-name." name:"socket-path" optional:"" short:"S" default:""`
 
 	Version bool `help:"Print version information and exit." short:"v"`
 
@@ -26,9 +27,20 @@ var CLI struct {
 		Reference string `arg:"" optional:"" help:"A reference to a command."`
 	} `cmd:"" help:"Recall the output of a previous command."`
 
+	Clients struct {
+	} `cmd:"" help:"List the clients connected to the cy server."`
+
+	Upgrade struct {
+	} `cmd:"" help:"Replace the running cy server with this version of cy without closing any panes."`
+
+	Takeover struct {
+	} `cmd:"" hidden:"" help:"Take over for a cy server that is being upgraded."`
+
 	Connect struct {
-		CPU   string `help:"Save a CPU performance report to the given path." name:"perf-file" optional:"" default:""`
-		Trace string `help:"Save a trace report to the given path." name:"trace-file" optional:"" default:""`
+		CPU      string `help:"Save a CPU performance report to the given path." name:"perf-file" optional:"" default:""`
+		Trace    string `help:"Save a trace report to the given path." name:"trace-file" optional:"" default:""`
+		ReadOnly bool   `help:"Watch panes without being able to send them input." name:"read-only"`
+		Follow   int32  `help:"Mirror the layout of the client with the given ID." optional:"" default:"0"`
 	} `cmd:"" default:"1" help:"Connect to the cy server, starting one if necessary."`
 }
 
@@ -106,6 +118,21 @@ func main() {
 		if err != nil {
 			writeError(err)
 		}
+	case "clients":
+		err := clientsCommand()
+		if err != nil {
+			writeError(err)
+		}
+	case "upgrade":
+		err := upgradeCommand()
+		if err != nil {
+			writeError(err)
+		}
+	case "takeover":
+		err := takeoverCommand()
+		if err != nil {
+			writeError(err)
+		}
 	case "connect":
 		err := connectCommand()
 		if err != nil {
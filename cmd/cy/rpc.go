@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cfoust/cy/pkg/cy"
+	"github.com/cfoust/cy/pkg/cy/api"
 	P "github.com/cfoust/cy/pkg/io/protocol"
 	"github.com/cfoust/cy/pkg/janet"
 	"github.com/cfoust/cy/pkg/mux/screen/tree"
@@ -20,8 +22,10 @@ const (
 )
 
 const (
-	RPCExec   = "exec"
-	RPCOutput = "output"
+	RPCExec    = "exec"
+	RPCOutput  = "output"
+	RPCUpgrade = "upgrade"
+	RPCClients = "clients"
 )
 
 type RPCExecArgs struct {
@@ -46,6 +50,21 @@ type RPCOutputResponse struct {
 	Data []byte
 }
 
+type RPCUpgradeArgs struct {
+	// The path to the executable that should replace the server.
+	Executable string
+}
+
+type RPCUpgradeResponse struct {
+}
+
+type RPCClientsArgs struct {
+}
+
+type RPCClientsResponse struct {
+	Clients []api.ClientInfo
+}
+
 // RPC executes an RPC call on the server over the given Connection.
 func RPC[S any, T any](
 	conn Connection,
@@ -69,6 +88,12 @@ func RPC[S any, T any](
 					return
 				}
 
+				// The server rejected the connection
+				if msg, ok := msg.Contents.(*P.ErrorMessage); ok {
+					errc <- fmt.Errorf("%s", msg.Message)
+					return
+				}
+
 				if msg.Contents.Type() != P.MessageTypeRPCResponse {
 					continue
 				}
@@ -126,13 +151,36 @@ func RPC[S any, T any](
 	return result, nil
 }
 
+// rpcAccess is the level of access a user needs to make each RPC call. Calls
+// not listed here require full access.
+var rpcAccess = map[string]cy.AccessLevel{
+	RPCExec:    cy.AccessFull,
+	RPCOutput:  cy.AccessReplayOnly,
+	RPCUpgrade: cy.AccessFull,
+	RPCClients: cy.AccessReadOnly,
+}
+
 // callRPC executes an RPC call and returns the result.
 func (s *Server) callRPC(
 	conn Connection,
+	user cy.User,
 	request *P.RPCRequestMessage,
 ) (interface{}, error) {
 	handle := new(codec.MsgpackHandle)
 
+	access, ok := rpcAccess[request.Name]
+	if !ok {
+		access = cy.AccessFull
+	}
+
+	if user.Access < access {
+		return nil, fmt.Errorf(
+			"user %s is not allowed to call %s",
+			user.Name,
+			request.Name,
+		)
+	}
+
 	switch request.Name {
 	case RPCExec:
 		var args RPCExecArgs
@@ -211,6 +259,31 @@ func (s *Server) callRPC(
 		return RPCOutputResponse{
 			Data: data,
 		}, nil
+	case RPCUpgrade:
+		var args RPCUpgradeArgs
+		if err := codec.NewDecoderBytes(
+			request.Args,
+			handle,
+		).Decode(&args); err != nil {
+			return nil, err
+		}
+
+		err := s.upgrade(args.Executable)
+		if err != nil {
+			return nil, err
+		}
+
+		return RPCUpgradeResponse{}, nil
+	case RPCClients:
+		response := RPCClientsResponse{}
+		for _, client := range s.cy.Clients() {
+			response.Clients = append(
+				response.Clients,
+				client.Info(),
+			)
+		}
+
+		return response, nil
 	}
 
 	return nil, fmt.Errorf("unknown RPC: %s", request.Name)
@@ -218,8 +291,12 @@ func (s *Server) callRPC(
 
 // HandleRPC handles an RPC request, calling the appropriate function and
 // encoding the response.
-func (s *Server) HandleRPC(conn Connection, request *P.RPCRequestMessage) {
-	response, err := s.callRPC(conn, request)
+func (s *Server) HandleRPC(
+	conn Connection,
+	user cy.User,
+	request *P.RPCRequestMessage,
+) {
+	response, err := s.callRPC(conn, user, request)
 
 	if err == nil && response == nil {
 		err = fmt.Errorf(
@@ -3,15 +3,19 @@ package main
 import (
 	"context"
 	"fmt"
-	"io"
+	"net"
 	"os"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/cfoust/cy/pkg/cy"
 	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/io/pipe"
 	P "github.com/cfoust/cy/pkg/io/protocol"
 	"github.com/cfoust/cy/pkg/io/ws"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/sevlyar/go-daemon"
 )
@@ -38,6 +42,16 @@ func getLock(lockPath string) (*os.File, error) {
 
 type Server struct {
 	cy *cy.Cy
+
+	socketPath string
+	listener   *net.UnixListener
+
+	upgradeLock sync.Mutex
+	// upgraded is closed once another server has taken over for this one.
+	upgraded chan struct{}
+
+	sessionLock sync.Mutex
+	sessions    map[*cy.Client]*session
 }
 
 type Connection = ws.Client[P.Message]
@@ -76,17 +90,51 @@ func (c *Client) Write(data []byte) (n int, err error) {
 
 func (s *Server) handleCyClient(
 	conn Connection,
+	events <-chan pipe.Packet[P.Message],
 	ws *Client,
+	user cy.User,
 	handshake *P.HandshakeMessage,
 ) error {
-	cy, err := s.cy.NewClient(conn.Ctx(), *handshake)
+	err := P.CheckClientVersion(handshake.Version, handshake.MinVersion)
 	if err != nil {
 		return err
 	}
 
-	events := conn.Receive()
+	// Clients outlive their connections so that they can be resumed if
+	// the connection drops
+	cy, resumed := s.cy.ResumeClient(*handshake, user)
+	if !resumed {
+		cy, err = s.cy.NewUserClient(s.cy.Ctx(), *handshake, user)
+		if err != nil {
+			return err
+		}
+	}
 
-	go func() { _, _ = io.Copy(ws, cy) }()
+	// However the connection ends, the client must be suspended so that
+	// it is either resumed or removed later. The only exception is an
+	// upgrade, which hands the client to the new server.
+	upgraded := false
+	defer func() {
+		if !upgraded {
+			cy.Suspend()
+		}
+	}()
+
+	// Clients that predate protocol versioning would not understand
+	// the response
+	if handshake.Version > 0 {
+		err = ws.conn.Send(P.HandshakeResponseMessage{
+			Version:      P.Version,
+			Capabilities: cy.Capabilities(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	session := s.getSession(cy)
+	session.setConn(ws)
+	defer session.setConn(nil)
 
 	for {
 		select {
@@ -95,6 +143,11 @@ func (s *Server) handleCyClient(
 		case <-cy.Ctx().Done():
 			ws.close()
 			return nil
+		case <-s.upgraded:
+			upgraded = true
+			ws.conn.Send(P.ReconnectMessage{})
+			ws.conn.Close()
+			return nil
 		case packet := <-events:
 			if packet.Error != nil {
 				// TODO(cfoust): 06/08/23 handle gracefully
@@ -120,11 +173,44 @@ func (s *Server) handleCyClient(
 	}
 }
 
+// authorize determines which user opened `conn` and what they are allowed
+// to do.
+func (s *Server) authorize(conn Connection) (user cy.User, err error) {
+	peer, ok := ws.GetPeer(conn.Ctx())
+	if !ok {
+		return user, fmt.Errorf("failed to verify peer credentials")
+	}
+
+	user, err = s.cy.Authorize(peer.UID)
+	if err != nil {
+		return user, err
+	}
+
+	if user.Access == cy.AccessNone {
+		return user, fmt.Errorf(
+			"user %s is not allowed to connect",
+			user.Name,
+		)
+	}
+
+	return user, nil
+}
+
 func (s *Server) HandleWSClient(conn Connection) {
 	events := conn.Receive()
 
 	wsClient := &Client{conn: conn}
 
+	user, err := s.authorize(conn)
+	if err != nil {
+		s.cy.Log(zerolog.WarnLevel, fmt.Sprintf(
+			"rejected connection: %s",
+			err,
+		))
+		wsClient.closeError(err)
+		return
+	}
+
 	for {
 		select {
 		case <-conn.Ctx().Done():
@@ -132,9 +218,12 @@ func (s *Server) HandleWSClient(conn Connection) {
 		case msg := <-events:
 			switch msg := msg.Contents.(type) {
 			case *P.HandshakeMessage:
+				// Messages must only be read by one goroutine
 				if err := s.handleCyClient(
 					conn,
+					events,
 					wsClient,
+					user,
 					msg,
 				); err != nil {
 					wsClient.closeError(err)
@@ -142,28 +231,84 @@ func (s *Server) HandleWSClient(conn Connection) {
 				}
 				return
 			case *P.RPCRequestMessage:
-				s.HandleRPC(conn, msg)
+				s.HandleRPC(conn, user, msg)
 			}
 		}
 	}
 
 }
 
-func serve(path string) error {
-	cy, err := cy.Start(context.Background(), cy.Options{
+func startCy(path string, handoff *cy.Handoff) (*cy.Cy, error) {
+	return cy.Start(context.Background(), cy.Options{
 		SocketPath: path,
 		SocketName: CLI.Socket,
 		Config:     cy.FindConfig(),
 		DataDir:    cy.FindDataDir(),
 		Shell:      getShell(),
+		Handoff:    handoff,
+	})
+}
+
+// serveListener serves cy clients on `listener` until cy exits or another
+// server takes over.
+func serveListener(
+	cy *cy.Cy,
+	path string,
+	listener *net.UnixListener,
+) error {
+	server := &Server{
+		cy:         cy,
+		socketPath: path,
+		listener:   listener,
+		upgraded:   make(chan struct{}),
+	}
+
+	// Sharing can be turned on and off at runtime, but the server that
+	// takes over after an upgrade is responsible for the socket
+	ctx, cancel := context.WithCancel(cy.Ctx())
+	go pollSharing(ctx, cy, path)
+
+	err := ws.ServeListener[P.Message](
+		cy.Ctx(),
+		listener,
+		path,
+		P.Protocol,
+		server,
+	)
+	cancel()
+
+	select {
+	case <-server.upgraded:
+		// Give clients time to receive the message telling them to
+		// reconnect to the new server
+		time.Sleep(UPGRADE_EXIT_DELAY)
+		os.Exit(0)
+	default:
+	}
+
+	return err
+}
+
+func serve(path string) error {
+	cy, err := startCy(path, nil)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{
+		Name: path,
+		Net:  "unix",
 	})
 	if err != nil {
 		return err
 	}
 
-	server := &Server{cy: cy}
+	err = shareSocket(path, cy.IsShared())
+	if err != nil {
+		return err
+	}
 
-	return ws.Serve[P.Message](cy.Ctx(), path, P.Protocol, server)
+	return serveListener(cy, path, listener)
 }
 
 func startServer(path string) error {
@@ -121,6 +121,48 @@ func TestHandshake(t *testing.T) {
 	require.NoError(t, conn.Ctx().Err())
 }
 
+func TestHandshakeVersion(t *testing.T) {
+	server := setupServer(t)
+	defer server.Release()
+
+	receive := func(minVersion int) P.Message {
+		conn, err := server.Connect()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		conn.Send(P.HandshakeMessage{
+			Env: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			Size: geom.Size{
+				R: 26,
+				C: 80,
+			},
+			Version:    P.Version,
+			MinVersion: minVersion,
+			Capabilities: []P.Capability{
+				P.CapabilityOSC52,
+			},
+		})
+
+		select {
+		case packet := <-conn.Receive():
+			require.NoError(t, packet.Error)
+			return packet.Contents
+		case <-time.After(time.Second):
+			require.FailNow(t, "no response to handshake")
+			return nil
+		}
+	}
+
+	require.Equal(t, &P.HandshakeResponseMessage{
+		Version:      P.Version,
+		Capabilities: []P.Capability{P.CapabilityOSC52},
+	}, receive(P.MinVersion))
+
+	require.IsType(t, &P.ErrorMessage{}, receive(P.Version+1))
+}
+
 func TestExec(t *testing.T) {
 	server := setupServer(t)
 	defer server.Release()
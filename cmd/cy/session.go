@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"sync"
+
+	"github.com/cfoust/cy/pkg/cy"
+)
+
+// session forwards the output of a cy client to whichever connection the
+// client is currently using. A client can outlive its connection, so
+// output produced while it has none is dropped; resuming the client
+// redraws the screen from scratch.
+type session struct {
+	sync.Mutex
+	conn *Client
+}
+
+func (s *session) Write(data []byte) (n int, err error) {
+	s.Lock()
+	conn := s.conn
+	s.Unlock()
+
+	if conn == nil {
+		return len(data), nil
+	}
+
+	// Errors mean that the connection dropped, which is handled
+	// elsewhere
+	_, _ = conn.Write(data)
+	return len(data), nil
+}
+
+// setConn changes the connection the session writes to. A nil connection
+// drops all output.
+func (s *session) setConn(conn *Client) {
+	s.Lock()
+	s.conn = conn
+	s.Unlock()
+}
+
+// getSession gets the session for `client`, creating one if it does not
+// exist. The session is removed when the client exits.
+func (s *Server) getSession(client *cy.Client) *session {
+	s.sessionLock.Lock()
+	defer s.sessionLock.Unlock()
+
+	if s.sessions == nil {
+		s.sessions = make(map[*cy.Client]*session)
+	}
+
+	if existing, ok := s.sessions[client]; ok {
+		return existing
+	}
+
+	sess := &session{}
+	s.sessions[client] = sess
+
+	go func() { _, _ = io.Copy(sess, client) }()
+	go func() {
+		<-client.Ctx().Done()
+		s.sessionLock.Lock()
+		delete(s.sessions, client)
+		s.sessionLock.Unlock()
+	}()
+
+	return sess
+}
@@ -1,24 +1,47 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/cfoust/cy/pkg/cy"
 	"github.com/cfoust/cy/pkg/sessions"
+
+	"github.com/rs/zerolog/log"
 )
 
 const (
 	CY_SOCKET_TEMPLATE = "/tmp/cy-%d"
 )
 
+// getSocketDirectory gets the directory containing the current user's
+// sockets.
+func getSocketDirectory() string {
+	return fmt.Sprintf(CY_SOCKET_TEMPLATE, os.Getuid())
+}
+
 // Much of the socket creation code is ported from tmux. (see tmux.c)
 // Part laziness, part I wanted cy to be as familiar as possible.
 func getSocketPath(name string) (string, error) {
-	uid := os.Getuid()
-	directory := fmt.Sprintf(CY_SOCKET_TEMPLATE, uid)
+	// An explicit path may point to a socket that belongs to another
+	// user, so the directory it is in is not checked
+	if len(CLI.SocketPath) > 0 {
+		return filepath.Abs(CLI.SocketPath)
+	}
+
+	directory := getSocketDirectory()
 
-	if err := sessions.EnsureDirectory(directory); err != nil {
+	// The directory is only traversable by other users while a server
+	// inside of it is shared
+	ensure := sessions.EnsureDirectory
+	if hasSharedSocket(directory) {
+		ensure = sessions.EnsureSharedDirectory
+	}
+
+	if err := ensure(directory); err != nil {
 		return "", err
 	}
 
@@ -29,3 +52,86 @@ func getSocketPath(name string) (string, error) {
 
 	return label, nil
 }
+
+// isSharedSocket reports whether other users can connect to the socket at
+// `path`.
+func isSharedSocket(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeSocket != 0 && info.Mode().Perm()&0007 != 0
+}
+
+// hasSharedSocket reports whether `directory` contains a socket that other
+// users can connect to.
+func hasSharedSocket(directory string) bool {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if isSharedSocket(filepath.Join(directory, entry.Name())) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shareSocket allows other users to connect to the socket at `path` if
+// `shared` is true and prevents them from doing so otherwise. Whether they
+// can actually use the server is determined by its :socket-users and
+// :socket-group parameters each time they connect.
+func shareSocket(path string, shared bool) error {
+	mode := os.FileMode(0700)
+	if shared {
+		mode = 0777
+	}
+
+	err := os.Chmod(path, mode)
+	if err != nil {
+		return err
+	}
+
+	directory := filepath.Dir(path)
+	if directory != getSocketDirectory() {
+		return nil
+	}
+
+	// Other servers in the directory may still be shared
+	mode = 0700
+	if hasSharedSocket(directory) {
+		mode = 0711
+	}
+
+	return os.Chmod(directory, mode)
+}
+
+// SHARE_POLL_INTERVAL is how often the server checks whether its
+// :socket-users and :socket-group parameters have changed.
+const SHARE_POLL_INTERVAL = time.Second
+
+// pollSharing keeps the permissions of the socket at `path` in sync with
+// whether `cy` is shared until `ctx` is done.
+func pollSharing(ctx context.Context, cy *cy.Cy, path string) {
+	shared := isSharedSocket(path)
+	for {
+		if cy.IsShared() != shared {
+			err := shareSocket(path, !shared)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to share socket")
+			} else {
+				shared = !shared
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(SHARE_POLL_INTERVAL):
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cfoust/cy/pkg/cy"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollSharing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := cy.Start(ctx, cy.Options{
+		Shell: "/bin/bash",
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "socket")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{
+		Name: path,
+		Net:  "unix",
+	})
+	require.NoError(t, err)
+	defer listener.Close()
+
+	require.NoError(t, shareSocket(path, server.IsShared()))
+	require.False(t, isSharedSocket(path))
+
+	go pollSharing(ctx, server, path)
+
+	set := func(code string) {
+		_, err := server.ExecuteOnBehalf(ctx, 0, []byte(code), "<test>")
+		require.NoError(t, err)
+	}
+
+	// Sharing can be turned on...
+	set(`(param/set :root :socket-users ["alice"])`)
+	require.Eventually(t, func() bool {
+		return isSharedSocket(path)
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// ...and off again at runtime
+	set(`(param/set :root :socket-users [])`)
+	require.Eventually(t, func() bool {
+		return !isSharedSocket(path)
+	}, 5*time.Second, 50*time.Millisecond)
+}
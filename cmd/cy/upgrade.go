@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/cfoust/cy/pkg/cy"
+)
+
+const (
+	// UPGRADE_TIMEOUT is how long the old server waits for the new server
+	// to start before it gives up.
+	UPGRADE_TIMEOUT = 10 * time.Second
+	// UPGRADE_EXIT_DELAY is how long the old server waits after the new
+	// server has taken over before it exits.
+	UPGRADE_EXIT_DELAY = 1 * time.Second
+	// TAKEOVER_FD is the file descriptor of the socket the new server uses
+	// to receive the state of the old one.
+	TAKEOVER_FD = 3
+)
+
+// upgrade replaces this server with a new one started from `executable`.
+// The new server takes over this server's socket and all of the processes
+// running in its panes, then clients are told to reconnect.
+func (s *Server) upgrade(executable string) error {
+	if !s.upgradeLock.TryLock() {
+		return fmt.Errorf("an upgrade is already in progress")
+	}
+	defer s.upgradeLock.Unlock()
+
+	if s.listener == nil {
+		return fmt.Errorf("this server cannot be upgraded")
+	}
+
+	listenerFile, err := s.listener.File()
+	if err != nil {
+		return err
+	}
+	defer listenerFile.Close()
+
+	fds, err := syscall.Socketpair(
+		syscall.AF_UNIX,
+		syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC,
+		0,
+	)
+	if err != nil {
+		return err
+	}
+
+	local := os.NewFile(uintptr(fds[0]), "upgrade")
+	remote := os.NewFile(uintptr(fds[1]), "takeover")
+	defer local.Close()
+
+	conn, err := net.FileConn(local)
+	if err != nil {
+		remote.Close()
+		return err
+	}
+	defer conn.Close()
+
+	logFile, err := os.OpenFile(
+		s.socketPath+".log",
+		os.O_WRONLY|os.O_CREATE|os.O_APPEND,
+		0600,
+	)
+	if err != nil {
+		remote.Close()
+		return err
+	}
+	defer logFile.Close()
+
+	handoff, err := s.cy.Handoff()
+	if err != nil {
+		remote.Close()
+		return err
+	}
+
+	cmd := exec.Command(
+		executable,
+		"--socket-name",
+		CLI.Socket,
+		"--socket-path",
+		s.socketPath,
+		"takeover",
+	)
+	cmd.ExtraFiles = []*os.File{remote}
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	err = cmd.Start()
+	remote.Close()
+	if err != nil {
+		handoff.Discard()
+		return fmt.Errorf("failed to start new server: %s", err)
+	}
+
+	fail := func(err error) error {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		handoff.Discard()
+		return fmt.Errorf("failed to upgrade: %s", err)
+	}
+
+	err = sendHandoff(
+		conn.(*net.UnixConn),
+		handoff.State,
+		append([]*os.File{listenerFile}, handoff.Files...),
+	)
+	if err != nil {
+		return fail(err)
+	}
+
+	// The new server acknowledges the handoff once it has started
+	err = conn.SetReadDeadline(time.Now().Add(UPGRADE_TIMEOUT))
+	if err != nil {
+		return fail(err)
+	}
+
+	_, err = io.ReadFull(conn, make([]byte, 1))
+	if err != nil {
+		return fail(err)
+	}
+
+	_ = cmd.Process.Release()
+	handoff.Release()
+
+	// upgraded must be closed before the listener so that the server does
+	// not exit before clients are told to reconnect. The socket file now
+	// belongs to the new server.
+	close(s.upgraded)
+	s.listener.SetUnlinkOnClose(false)
+	s.listener.Close()
+	return nil
+}
+
+// upgradeCommand is the entrypoint for the upgrade command.
+func upgradeCommand() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	socketPath, err := getSocketPath(CLI.Socket)
+	if err != nil {
+		return err
+	}
+
+	conn, err := connect(socketPath, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = RPC[RPCUpgradeArgs, RPCUpgradeResponse](
+		conn,
+		RPCUpgrade,
+		RPCUpgradeArgs{
+			Executable: executable,
+		},
+	)
+	return err
+}
+
+// takeoverCommand is the entrypoint for the takeover command, which is run
+// by a server that is being upgraded to start the server that replaces it.
+func takeoverCommand() error {
+	socketPath, err := getSocketPath(CLI.Socket)
+	if err != nil {
+		return err
+	}
+
+	// FileConn duplicates the descriptor, so the original must be closed
+	// explicitly rather than by the garbage collector, which could close
+	// an unrelated file that reused it.
+	file := os.NewFile(TAKEOVER_FD, "takeover")
+	conn, err := net.FileConn(file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return fmt.Errorf("takeover socket was not a Unix socket")
+	}
+
+	state, files, err := receiveHandoff(unixConn)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("no listener was provided")
+	}
+
+	l, err := net.FileListener(files[0])
+	files[0].Close()
+	if err != nil {
+		return err
+	}
+
+	listener, ok := l.(*net.UnixListener)
+	if !ok {
+		return fmt.Errorf("listener was not a Unix socket")
+	}
+
+	cy, err := startCy(socketPath, &cy.Handoff{
+		State: state,
+		Files: files[1:],
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Write([]byte{0})
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(
+		socketPath+".pid",
+		[]byte(strconv.Itoa(os.Getpid())),
+		0644,
+	)
+	if err != nil {
+		return err
+	}
+
+	return serveListener(cy, socketPath, listener)
+}
@@ -0,0 +1,155 @@
+package cy
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cfoust/cy/pkg/janet"
+)
+
+// AccessLevel determines what a user connected to the server is allowed to
+// do.
+type AccessLevel int
+
+const (
+	// The user cannot connect to the server.
+	AccessNone AccessLevel = iota
+	// The user can only read the output of commands, such as with
+	// `cy recall`.
+	AccessReplayOnly
+	// The user can connect read-only clients.
+	AccessReadOnly
+	// The user can do anything the owner of the server can.
+	AccessFull
+)
+
+var accessKeywords = map[AccessLevel]janet.Keyword{
+	AccessNone:       "none",
+	AccessReplayOnly: "replay-only",
+	AccessReadOnly:   "read-only",
+	AccessFull:       "full",
+}
+
+func (a AccessLevel) String() string {
+	return string(accessKeywords[a])
+}
+
+func parseAccessLevel(value string) (AccessLevel, error) {
+	switch value {
+	case "full":
+		return AccessFull, nil
+	case "read-only":
+		return AccessReadOnly, nil
+	case "replay-only":
+		return AccessReplayOnly, nil
+	}
+
+	return AccessNone, fmt.Errorf(
+		"invalid access level %s, must be one of full, read-only, or replay-only",
+		value,
+	)
+}
+
+// User is the Unix user on whose behalf a client or command connected to
+// the server.
+type User struct {
+	UID    int
+	Name   string
+	Access AccessLevel
+}
+
+// Owner returns the User that started the server.
+func (c *Cy) Owner() User {
+	uid := os.Getuid()
+	owner := User{
+		UID:    uid,
+		Name:   strconv.Itoa(uid),
+		Access: AccessFull,
+	}
+
+	if u, err := user.LookupId(owner.Name); err == nil {
+		owner.Name = u.Username
+	}
+
+	return owner
+}
+
+// IsShared reports whether users other than the owner of the server may
+// connect to it.
+func (c *Cy) IsShared() bool {
+	params := c.tree.Root().Params()
+	return params.SocketGroup() != "" || len(params.SocketUsers()) > 0
+}
+
+// Authorize determines the level of access the Unix user with the given
+// uid has to the server according to the :socket-users and :socket-group
+// parameters. The owner of the server and root always have full access.
+func (c *Cy) Authorize(uid int) (User, error) {
+	id := strconv.Itoa(uid)
+	authorized := User{
+		UID:  uid,
+		Name: id,
+	}
+
+	u, lookupErr := user.LookupId(id)
+	if lookupErr == nil {
+		authorized.Name = u.Username
+	}
+
+	if uid == os.Getuid() || uid == 0 {
+		authorized.Access = AccessFull
+		return authorized, nil
+	}
+
+	params := c.tree.Root().Params()
+	for _, entry := range params.SocketUsers() {
+		name, level, found := strings.Cut(entry, ":")
+		if name != id && (lookupErr != nil || name != u.Username) {
+			continue
+		}
+
+		if !found {
+			authorized.Access = AccessFull
+			return authorized, nil
+		}
+
+		access, err := parseAccessLevel(level)
+		if err != nil {
+			return authorized, err
+		}
+
+		authorized.Access = access
+		return authorized, nil
+	}
+
+	groupName := params.SocketGroup()
+	if groupName == "" || lookupErr != nil {
+		return authorized, nil
+	}
+
+	group, err := user.LookupGroup(groupName)
+	if err != nil {
+		return authorized, err
+	}
+
+	groups, err := u.GroupIds()
+	if err != nil {
+		return authorized, err
+	}
+
+	if !slices.Contains(groups, group.Gid) {
+		return authorized, nil
+	}
+
+	access, err := parseAccessLevel(params.SocketGroupAccess())
+	if err != nil {
+		return authorized, err
+	}
+
+	authorized.Access = access
+	return authorized, nil
+}
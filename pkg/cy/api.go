@@ -127,6 +127,16 @@ func (c *CyModule) Trace(user interface{}) error {
 	return nil
 }
 
+func (c *CyModule) Copy(user interface{}, text string) error {
+	client, ok := user.(*Client)
+	if !ok {
+		return fmt.Errorf("missing client context")
+	}
+
+	client.buffer = text
+	return nil
+}
+
 func (c *CyModule) Paste(user interface{}) {
 	client, ok := user.(*Client)
 	if !ok {
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/janet"
+	"github.com/cfoust/cy/pkg/layout"
+	"github.com/cfoust/cy/pkg/mux/screen/toasts"
+	"github.com/cfoust/cy/pkg/mux/screen/tree"
+)
+
+// ClientInfo describes a client connected to the cy server.
+type ClientInfo struct {
+	// The unique identifier of the client.
+	ID int32 `janet:"id"`
+	// The size of the client's terminal.
+	Size geom.Vec2
+	// The color profile of the client's terminal, which is one of :ascii,
+	// :ansi, :ansi256, or :truecolor.
+	Profile janet.Keyword
+	// The NodeID of the pane the client is attached to, if any.
+	Node *tree.NodeID
+	// The time at which the client connected in seconds since the Unix
+	// epoch.
+	Connected float64
+	// Whether the client connected over SSH.
+	SSH bool `janet:"ssh"`
+	// Whether the client is prevented from sending input to panes.
+	ReadOnly bool
+	// The ID of the client whose layout this client mirrors, if any.
+	Following *int32
+	// The name of the Unix user the client connected as.
+	User string
+	// The uid of the Unix user the client connected as.
+	UID int `janet:"uid"`
+	// The user's level of access to the server, which is one of :full,
+	// :read-only, or :replay-only.
+	Access janet.Keyword
+	// Whether the client's connection dropped and the server is waiting
+	// for it to reconnect.
+	Suspended bool
+	// The features of the client's terminal that cy supports, such as
+	// :synchronized-output.
+	Capabilities []janet.Keyword
+}
+
+type ClientModule struct {
+	Server Server
+}
+
+func (c *ClientModule) getClient(id int) (Client, error) {
+	for _, client := range c.Server.Clients() {
+		if client.Info().ID == int32(id) {
+			return client, nil
+		}
+	}
+
+	return nil, fmt.Errorf("client %d not found", id)
+}
+
+// List returns interface{} (as does Info) because ClientInfo contains a
+// pointer, which is not a valid return type for a callback.
+func (c *ClientModule) List() interface{} {
+	infos := make([]ClientInfo, 0)
+	for _, client := range c.Server.Clients() {
+		infos = append(infos, client.Info())
+	}
+	return infos
+}
+
+func (c *ClientModule) Current(context interface{}) (int32, error) {
+	client, err := getClient(context)
+	if err != nil {
+		return 0, err
+	}
+
+	return client.Info().ID, nil
+}
+
+func (c *ClientModule) Info(id int) (interface{}, error) {
+	client, err := c.getClient(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Info(), nil
+}
+
+// Env returns interface{} because maps are not a valid return type for a
+// callback.
+func (c *ClientModule) Env(id int) (interface{}, error) {
+	client, err := c.getClient(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Env(), nil
+}
+
+func (c *ClientModule) Detach(id int) error {
+	client, err := c.getClient(id)
+	if err != nil {
+		return err
+	}
+
+	client.Detach()
+	return nil
+}
+
+func (c *ClientModule) Follow(id int, leader int) error {
+	client, err := c.getClient(id)
+	if err != nil {
+		return err
+	}
+
+	return client.Follow(int32(leader))
+}
+
+func (c *ClientModule) Unfollow(id int) error {
+	client, err := c.getClient(id)
+	if err != nil {
+		return err
+	}
+
+	client.Unfollow()
+	return nil
+}
+
+func (c *ClientModule) Toast(
+	id int,
+	level *janet.Value,
+	message string,
+) error {
+	defer level.Free()
+
+	toastLevel, err := resolveLevel(level)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.getClient(id)
+	if err != nil {
+		return err
+	}
+
+	client.Toast(toasts.Toast{
+		Level:   toastLevel,
+		Message: message,
+	})
+	return nil
+}
+
+func (c *ClientModule) SetLayout(id int, value *janet.Value) error {
+	defer value.Free()
+
+	var layout layout.Layout
+	err := value.Unmarshal(&layout)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.getClient(id)
+	if err != nil {
+		return err
+	}
+
+	return client.SetLayout(layout)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/cfoust/cy/pkg/mux/stream"
 	"github.com/cfoust/cy/pkg/replay"
 	"github.com/cfoust/cy/pkg/replay/detect"
+	"github.com/cfoust/cy/pkg/replay/diff"
 	"github.com/cfoust/cy/pkg/util"
 )
 
@@ -40,9 +41,17 @@ func (c *CmdModule) New(
 		return 0, err
 	}
 
+	env := map[string]string{}
 	command := "/bin/bash"
 	if client, ok := user.(Client); ok {
 		command = client.Params().DefaultShell()
+
+		clientEnv := client.Env()
+		for _, name := range client.Params().UpdateEnvironment() {
+			if value, ok := clientEnv[name]; ok {
+				env[name] = value
+			}
+		}
 	}
 
 	values := cmdParams.WithDefault(CmdParams{
@@ -50,6 +59,7 @@ func (c *CmdModule) New(
 	})
 
 	id, create := group.NewPaneCreator(c.Lifetime.Ctx())
+	env["CY"] = fmt.Sprintf("%s:%d", c.Server.SocketName(), id)
 
 	replayable, err := cmd.New(
 		c.Lifetime.Ctx(),
@@ -58,13 +68,7 @@ func (c *CmdModule) New(
 			Args:      values.Args,
 			Directory: values.Path,
 			Restart:   values.Restart,
-			Env: map[string]string{
-				"CY": fmt.Sprintf(
-					"%s:%d",
-					c.Server.SocketName(),
-					id,
-				),
-			},
+			Env:       env,
 		},
 		group.Params().DataDirectory(),
 		c.TimeBinds,
@@ -125,3 +129,24 @@ func (c *CmdModule) Commands(id *janet.Value) (*[]detect.Command, error) {
 	commands := r.Commands()
 	return &commands, nil
 }
+
+func (c *CmdModule) DiffOutput(id *janet.Value, a, b int) (string, error) {
+	defer id.Free()
+
+	pane, err := resolvePane(c.Tree, id)
+	if err != nil {
+		return "", err
+	}
+
+	r, ok := pane.Screen().(*replay.Replayable)
+	if !ok {
+		return "", fmt.Errorf("pane was not a cmd")
+	}
+
+	lines, err := r.DiffCommands(a, b)
+	if err != nil {
+		return "", err
+	}
+
+	return diff.String(lines), nil
+}
@@ -5,10 +5,11 @@ import (
 )
 
 var (
-	KEYWORD_ROOT   = janet.Keyword("root")
-	KEYWORD_TIME   = janet.Keyword("time")
-	KEYWORD_COPY   = janet.Keyword("copy")
-	KEYWORD_CLIENT = janet.Keyword("client")
+	KEYWORD_ROOT      = janet.Keyword("root")
+	KEYWORD_TIME      = janet.Keyword("time")
+	KEYWORD_COPY      = janet.Keyword("copy")
+	KEYWORD_CLIENT    = janet.Keyword("client")
+	KEYWORD_READ_ONLY = janet.Keyword("read-only")
 
 	KEYWORD_RE = janet.Keyword("re")
 )
@@ -131,3 +131,12 @@ var _ janet.Documented = (*StyleModule)(nil)
 func (s *StyleModule) Documentation() string {
 	return DOCS_STYLE
 }
+
+//go:embed docs-client.md
+var DOCS_CLIENT string
+
+var _ janet.Documented = (*ClientModule)(nil)
+
+func (i *ClientModule) Documentation() string {
+	return DOCS_CLIENT
+}
@@ -2,17 +2,23 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
+	"regexp"
 
 	"github.com/cfoust/cy/pkg/anim"
 	"github.com/cfoust/cy/pkg/geom"
 	"github.com/cfoust/cy/pkg/input/fuzzy"
+	"github.com/cfoust/cy/pkg/input/hint"
+	"github.com/cfoust/cy/pkg/input/panes"
 	"github.com/cfoust/cy/pkg/input/text"
 	"github.com/cfoust/cy/pkg/janet"
 	"github.com/cfoust/cy/pkg/mux/screen"
 	"github.com/cfoust/cy/pkg/mux/screen/server"
 	"github.com/cfoust/cy/pkg/mux/screen/tree"
 	"github.com/cfoust/cy/pkg/util"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 type InputModule struct {
@@ -258,3 +264,155 @@ func (i *InputModule) Text(
 		return nil, ctx.Err()
 	}
 }
+
+type HintParams struct {
+	Patterns *[]string
+	Alphabet *string
+}
+
+func (i *InputModule) Hint(
+	ctx context.Context,
+	context interface{},
+	named *janet.Named[HintParams],
+) (interface{}, error) {
+	params := named.Values()
+
+	client, err := getClient(context)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := client.Params().HintPatterns()
+	if params.Patterns != nil {
+		patterns = *params.Patterns
+	}
+
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid pattern %s: %s",
+				pattern,
+				err,
+			)
+		}
+		compiled = append(compiled, re)
+	}
+
+	outerLayers := client.OuterLayers()
+	initial := outerLayers.State().Image
+
+	matches := hint.Find(initial, compiled...)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	alphabet := hint.DEFAULT_ALPHABET
+	if params.Alphabet != nil && len(*params.Alphabet) > 0 {
+		alphabet = *params.Alphabet
+	}
+
+	err = hint.CheckAlphabet(alphabet, matches)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Params().SkipInput() {
+		return matches[0].Text, nil
+	}
+
+	clientParams := client.Params()
+	result := make(chan interface{})
+	overlay := hint.New(
+		ctx,
+		initial,
+		hint.WithResult(result),
+		hint.WithPatterns(compiled...),
+		hint.WithAlphabet(alphabet),
+		hint.WithColors(
+			lipgloss.Color(clientParams.HintLabelColor()),
+			lipgloss.Color(clientParams.HintTypedColor()),
+			lipgloss.Color(clientParams.HintMatchColor()),
+		),
+	)
+
+	outerLayers.NewLayer(
+		overlay.Ctx(),
+		overlay,
+		screen.PositionTop,
+		screen.WithInteractive,
+		screen.WithOpaque,
+	)
+
+	select {
+	case match := <-result:
+		return match, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Pane labels each pane visible in the client's layout and returns the index
+// of the one the user chooses.
+func (i *InputModule) Pane(
+	ctx context.Context,
+	context interface{},
+) (*int, error) {
+	client, err := getClient(context)
+	if err != nil {
+		return nil, err
+	}
+
+	regions := client.LayoutPanes()
+	if len(regions) == 0 {
+		return nil, nil
+	}
+
+	if client.Params().SkipInput() {
+		return &regions[0].Index, nil
+	}
+
+	var targets []panes.Target
+	for _, region := range regions {
+		targets = append(targets, panes.Target{
+			Rect:   region.Rect,
+			Value:  region.Index,
+			Active: region.Config.Attached,
+		})
+	}
+
+	params := client.Params()
+	outerLayers := client.OuterLayers()
+	result := make(chan interface{})
+	overlay := panes.New(
+		ctx,
+		outerLayers.State().Image,
+		targets,
+		panes.WithResult(result),
+		panes.WithLabels(params.PaneLabels()),
+		panes.WithColors(
+			lipgloss.Color(params.PaneLabelColor()),
+			lipgloss.Color(params.PaneLabelActiveColor()),
+		),
+	)
+
+	outerLayers.NewLayer(
+		overlay.Ctx(),
+		overlay,
+		screen.PositionTop,
+		screen.WithInteractive,
+		screen.WithOpaque,
+	)
+
+	select {
+	case value := <-result:
+		index, ok := value.(int)
+		if !ok {
+			return nil, nil
+		}
+		return &index, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -10,8 +10,8 @@ import (
 )
 
 type KeyModule struct {
-	Tree                 *tree.Tree
-	TimeBinds, CopyBinds *bind.BindScope
+	Tree                                *tree.Tree
+	TimeBinds, CopyBinds, ReadOnlyBinds *bind.BindScope
 }
 
 type regexKey struct {
@@ -80,7 +80,12 @@ func (k *KeyModule) getScope(target *janet.Value) (*bind.BindScope, error) {
 		return k.CopyBinds, nil
 	}
 
-	return nil, fmt.Errorf("target must be one of :root, :time, :copy, or node ID")
+	err = target.Unmarshal(&KEYWORD_READ_ONLY)
+	if err == nil {
+		return k.ReadOnlyBinds, nil
+	}
+
+	return nil, fmt.Errorf("target must be one of :root, :time, :copy, :read-only, or node ID")
 }
 
 type BindParams struct {
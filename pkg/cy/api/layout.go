@@ -1,11 +1,16 @@
 package api
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/cfoust/cy/pkg/janet"
 	"github.com/cfoust/cy/pkg/layout"
+	"github.com/cfoust/cy/pkg/mux/screen/tree"
 )
 
 type LayoutModule struct {
+	Tree *tree.Tree
 }
 
 func (l *LayoutModule) Set(context interface{}, value *janet.Value) error {
@@ -33,3 +38,131 @@ func (l *LayoutModule) Get(context interface{}) (*layout.Layout, error) {
 	layout := client.GetLayout()
 	return &layout, nil
 }
+
+func (l *LayoutModule) Undo(context interface{}) (bool, error) {
+	client, err := getClient(context)
+	if err != nil {
+		return false, err
+	}
+
+	return client.UndoLayout()
+}
+
+func (l *LayoutModule) Redo(context interface{}) (bool, error) {
+	client, err := getClient(context)
+	if err != nil {
+		return false, err
+	}
+
+	return client.RedoLayout()
+}
+
+type ZoomParams struct {
+	Decorations *bool
+}
+
+func (l *LayoutModule) Zoom(
+	context interface{},
+	named *janet.Named[ZoomParams],
+) error {
+	client, err := getClient(context)
+	if err != nil {
+		return err
+	}
+
+	params := named.Values()
+	keepDecorations := true
+	if params.Decorations != nil {
+		keepDecorations = *params.Decorations
+	}
+
+	return client.ZoomLayout(keepDecorations)
+}
+
+func (l *LayoutModule) Unzoom(context interface{}) error {
+	client, err := getClient(context)
+	if err != nil {
+		return err
+	}
+
+	return client.UnzoomLayout()
+}
+
+func (l *LayoutModule) IsZoomed(context interface{}) (bool, error) {
+	client, err := getClient(context)
+	if err != nil {
+		return false, err
+	}
+
+	return client.IsLayoutZoomed(), nil
+}
+
+// TILING_STRATEGIES are the strategies supported by layout/tile.
+var TILING_STRATEGIES = []janet.Keyword{
+	"even-horizontal",
+	"even-vertical",
+	"main-vertical",
+	"main-horizontal",
+	"tiled",
+}
+
+// Tiling describes a group whose panes are arranged automatically using a
+// tiling strategy whenever panes are added to or removed from it.
+type Tiling struct {
+	Group    tree.NodeID
+	Strategy janet.Keyword
+}
+
+func (l *LayoutModule) AutoTile(
+	context interface{},
+	groupId *janet.Value,
+	strategy *janet.Value,
+) error {
+	defer groupId.Free()
+	defer strategy.Free()
+
+	client, err := getClient(context)
+	if err != nil {
+		return err
+	}
+
+	group, err := resolveGroup(l.Tree, groupId)
+	if err != nil {
+		return err
+	}
+
+	var keyword janet.Keyword
+	err = strategy.Unmarshal(&keyword)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(TILING_STRATEGIES, keyword) {
+		return fmt.Errorf("unknown tiling strategy: :%s", keyword)
+	}
+
+	client.SetTiling(&Tiling{
+		Group:    group.Id(),
+		Strategy: keyword,
+	})
+	return nil
+}
+
+func (l *LayoutModule) DisableAutoTile(context interface{}) error {
+	client, err := getClient(context)
+	if err != nil {
+		return err
+	}
+
+	client.SetTiling(nil)
+	return nil
+}
+
+func (l *LayoutModule) GetAutoTile(context interface{}) (*Tiling, error) {
+	client, err := getClient(context)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetTiling(), nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/cfoust/cy/pkg/frames"
 	"github.com/cfoust/cy/pkg/layout"
+	"github.com/cfoust/cy/pkg/layout/engine"
 	"github.com/cfoust/cy/pkg/mux/screen"
 	"github.com/cfoust/cy/pkg/mux/screen/toasts"
 	"github.com/cfoust/cy/pkg/mux/screen/tree"
@@ -23,15 +24,33 @@ type Client interface {
 	OuterLayers() *screen.Layers
 	SetLayout(layout.Layout) error
 	GetLayout() layout.Layout
+	UndoLayout() (bool, error)
+	RedoLayout() (bool, error)
+	ZoomLayout(keepDecorations bool) error
+	UnzoomLayout() error
+	IsLayoutZoomed() bool
+	LayoutPanes() []engine.PaneRegion
+	SetTiling(*Tiling)
+	GetTiling() *Tiling
 	Frame() *frames.Framer
 	Binds() []Binding
 	Toast(toasts.Toast)
+	Info() ClientInfo
+	// Env returns the environment variables the client had when it
+	// connected.
+	Env() map[string]string
+	Detach()
+	Follow(id int32) error
+	Unfollow()
 }
 
 type Server interface {
 	SocketName() string
 	ExecuteJanet(path string) error
 	Log(level zerolog.Level, message string)
+	// Clients returns all of the clients connected to the server in the
+	// order they connected.
+	Clients() []Client
 }
 
 func getClient(context interface{}) (Client, error) {
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	_ "embed"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"github.com/cfoust/cy/pkg/janet"
 	"github.com/cfoust/cy/pkg/mux/screen/tree"
 	"github.com/cfoust/cy/pkg/replay"
+	"github.com/cfoust/cy/pkg/replay/diff"
 	"github.com/cfoust/cy/pkg/replay/player"
 	"github.com/cfoust/cy/pkg/sessions"
 	"github.com/cfoust/cy/pkg/taro"
@@ -188,6 +190,125 @@ func (m *ReplayModule) CommandSelectBackward(context interface{}) error {
 	return m.sendAction(context, replay.ActionCommandSelectBackward)
 }
 
+func (m *ReplayModule) GotoTime(context interface{}) error {
+	return m.sendAction(context, replay.ActionGotoTime)
+}
+
+func (m *ReplayModule) SeekTime(context interface{}, value string) error {
+	if _, err := replay.ParseTimeTarget(value); err != nil {
+		return err
+	}
+
+	return m.send(context, replay.ActionEvent{
+		Type: replay.ActionGotoTime,
+		Arg:  value,
+	})
+}
+
+func (m *ReplayModule) Follow(context interface{}) error {
+	return m.sendAction(context, replay.ActionFollow)
+}
+
+func (m *ReplayModule) Mark(context interface{}) error {
+	return m.sendAction(context, replay.ActionMark)
+}
+
+func (m *ReplayModule) Diff(context interface{}) error {
+	return m.sendAction(context, replay.ActionDiff)
+}
+
+func (m *ReplayModule) DiffText(id *janet.Value, a, b int) (string, error) {
+	defer id.Free()
+
+	pane, err := resolvePane(m.Tree, id)
+	if err != nil {
+		return "", err
+	}
+
+	r, ok := pane.Screen().(*replay.Replayable)
+	if !ok {
+		return "", fmt.Errorf("node not replayable")
+	}
+
+	lines, err := r.DiffScreens(a, b)
+	if err != nil {
+		return "", err
+	}
+
+	return diff.String(lines), nil
+}
+
+func (m *ReplayModule) CopyExport(context interface{}, format string) error {
+	if _, err := replay.ParseExportFormat(format); err != nil {
+		return err
+	}
+
+	return m.send(context, replay.ActionEvent{
+		Type: replay.ActionExport,
+		Arg:  format,
+	})
+}
+
+type ExportParams struct {
+	From    *int
+	To      *int
+	Command *int
+}
+
+func (m *ReplayModule) Export(
+	id *janet.Value,
+	format string,
+	named *janet.Named[ExportParams],
+) (string, error) {
+	defer id.Free()
+
+	exportFormat, err := replay.ParseExportFormat(format)
+	if err != nil {
+		return "", err
+	}
+
+	pane, err := resolvePane(m.Tree, id)
+	if err != nil {
+		return "", err
+	}
+
+	r, ok := pane.Screen().(*replay.Replayable)
+	if !ok {
+		return "", fmt.Errorf("node not replayable")
+	}
+
+	// By default the range covers the pane's entire history
+	params := named.Values()
+	from, to := params.From, -1
+	if params.Command != nil {
+		command, err := r.Command(*params.Command)
+		if err != nil {
+			return "", err
+		}
+
+		if from == nil {
+			from = &command.Executed
+		}
+		to = command.Completed
+	}
+
+	if params.To != nil {
+		to = *params.To
+	}
+
+	var b bytes.Buffer
+	if from == nil {
+		err = r.ExportFromStart(&b, exportFormat, to)
+	} else {
+		err = r.Export(&b, exportFormat, *from, to)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
 func (m *ReplayModule) StartOfLine(context interface{}) error {
 	return m.sendAction(context, replay.ActionStartOfLine)
 }
@@ -305,6 +426,7 @@ func (m *ReplayModule) OpenFile(
 type ReplayParams struct {
 	Main     bool
 	Copy     bool
+	Follow   bool
 	Location *geom.Vec2
 }
 
@@ -330,6 +452,10 @@ func (m *ReplayModule) Open(
 		options = append(options, replay.WithCopyMode)
 	}
 
+	if params.Follow {
+		options = append(options, replay.WithFollow)
+	}
+
 	if params.Location != nil {
 		options = append(options, replay.WithLocation(
 			*params.Location,
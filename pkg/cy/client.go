@@ -3,6 +3,8 @@ package cy
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/cfoust/cy/pkg/bind"
@@ -21,9 +23,11 @@ import (
 	"github.com/cfoust/cy/pkg/mux/screen/tree"
 	"github.com/cfoust/cy/pkg/mux/stream/renderer"
 	"github.com/cfoust/cy/pkg/params"
+	"github.com/cfoust/cy/pkg/replay"
 	"github.com/cfoust/cy/pkg/taro"
 	"github.com/cfoust/cy/pkg/util"
 
+	"github.com/muesli/termenv"
 	"github.com/sasha-s/go-deadlock"
 	"github.com/xo/terminfo"
 )
@@ -41,6 +45,14 @@ type Client struct {
 
 	cy *Cy
 
+	// the Unix user the client connected as
+	user User
+	// the secret the client uses to resume after its connection drops
+	token string
+	// removes the client if it is not resumed in time, set while the
+	// client is suspended
+	expiry *time.Timer
+
 	// All of the environment variables in the client's original
 	// environment at connection time
 	env Environment
@@ -51,6 +63,19 @@ type Client struct {
 	// the text the client has copied
 	buffer string
 
+	// the size of the client's terminal
+	size geom.Vec2
+	// the color profile of the client's terminal
+	profile termenv.Profile
+	// whether the client is prevented from sending input to panes
+	readOnly bool
+	// the features of the client's terminal that cy supports
+	capabilities []P.Capability
+
+	// the client whose layout this client mirrors, if any
+	leader    *Client
+	following *util.Lifetime
+
 	// the client can have params of their own
 	params *params.Parameters
 
@@ -66,16 +91,57 @@ type Client struct {
 	history []tree.NodeID
 	// The client's current location in history.
 	historyIndex int
+
+	// The layouts replaced by changes to the client's layout.
+	layoutHistory layout.History
+
+	// The group the client's layout is tiling automatically, if any.
+	tiling *api.Tiling
+	// The panes in that group when the layout was last tiled.
+	tiledPanes []tree.NodeID
 }
 
 var _ api.Client = (*Client)(nil)
 
 var _ mux.Stream = (*Client)(nil)
 
+// NewClient creates a client on behalf of the owner of the server.
 func (c *Cy) NewClient(ctx context.Context, options ClientOptions) (*Client, error) {
+	return c.NewUserClient(ctx, options, c.Owner())
+}
+
+// NewUserClient creates a client on behalf of `user`, who must be allowed
+// to connect clients. Users with read-only access can only connect
+// read-only clients.
+func (c *Cy) NewUserClient(
+	ctx context.Context,
+	options ClientOptions,
+	user User,
+) (*Client, error) {
+	switch user.Access {
+	case AccessFull:
+	case AccessReadOnly:
+		options.ReadOnly = true
+	default:
+		return nil, fmt.Errorf(
+			"user %s is not allowed to connect clients",
+			user.Name,
+		)
+	}
+
+	if options.Follow != 0 {
+		if _, ok := c.getClient(options.Follow); !ok {
+			return nil, fmt.Errorf(
+				"client %d not found",
+				options.Follow,
+			)
+		}
+	}
+
 	client := &Client{
 		Lifetime: util.NewLifetime(ctx),
 		cy:       c,
+		user:     user,
 		params:   params.New(),
 		binds:    bind.NewEngine[bind.Action](),
 	}
@@ -87,18 +153,39 @@ func (c *Cy) NewClient(ctx context.Context, options ClientOptions) (*Client, err
 
 	client.params.SetParent(c.tree.Root().Params())
 
+	client.id = c.nextClientID.Add(1)
+
 	c.Lock()
 	c.clients = append(c.clients, client)
 	c.Unlock()
 
-	client.id = c.nextClientID.Add(1)
-
 	go client.pollEvents()
 	go client.binds.Poll(client.Ctx())
 
-	err = client.findNewPane()
-	if err != nil {
-		return nil, err
+	// A layout from a resurrected snapshot takes the place of a new
+	// shell
+	if code, ok := c.popResurrectedLayout(); ok {
+		client.restoreLayout(code)
+	}
+
+	if options.Follow != 0 {
+		err = client.Follow(options.Follow)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The layout is restored before looking for a pane so that no shell
+	// is created just to be replaced by the restored layout
+	if client.Node() == nil && client.params.RestoreLayout() {
+		client.restoreLayout("(layout/restore-last)")
+	}
+
+	if client.Node() == nil {
+		err = client.findNewPane()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	go func() {
@@ -132,6 +219,10 @@ func (c *Client) Write(data []byte) (n int, err error) {
 }
 
 func (c *Client) Resize(size geom.Vec2) error {
+	c.Lock()
+	c.size = size
+	c.Unlock()
+
 	c.muxClient.Resize(size)
 	c.renderer.Resize(size)
 	return nil
@@ -181,6 +272,10 @@ func (c *Client) pollEvents() {
 				continue
 			}
 
+			if !c.canSend() {
+				continue
+			}
+
 			// We only consider key presses to be an interaction
 			// We don't want mouse motion to trigger this
 			if _, ok := event.(taro.KeyMsg); ok {
@@ -197,6 +292,29 @@ func (c *Client) pollEvents() {
 	}
 }
 
+// canSend reports whether input that does not trigger a binding should be
+// passed on to the client's screen. Read-only clients can only send input
+// to a pane that is in replay mode, since that does not affect the pane's
+// process.
+func (c *Client) canSend() bool {
+	c.RLock()
+	readOnly := c.readOnly
+	node := c.node
+	c.RUnlock()
+
+	if !readOnly {
+		return true
+	}
+
+	pane, ok := node.(*tree.Pane)
+	if !ok {
+		return false
+	}
+
+	r, ok := pane.Screen().(*replay.Replayable)
+	return ok && r.IsReplayMode()
+}
+
 func (c *Client) Node() tree.Node {
 	c.RLock()
 	defer c.RUnlock()
@@ -230,6 +348,11 @@ func (c *Client) initialize(options ClientOptions) error {
 	defer c.Unlock()
 
 	c.env = Environment(options.Env)
+	c.size = options.Size
+	c.profile = options.Profile
+	c.readOnly = options.ReadOnly
+	c.token = options.Token
+	c.capabilities = P.Negotiate(options.Capabilities)
 
 	info, err := terminfo.Load(c.env.Default("TERM", "xterm-256color"))
 	if err != nil {
@@ -284,7 +407,8 @@ func (c *Client) initialize(options ClientOptions) error {
 		screen.WithOpaque,
 	)
 
-	if !c.cy.options.HideSplash {
+	// Read-only clients could never dismiss the splash screen
+	if !c.cy.options.HideSplash && !options.ReadOnly {
 		splashScreen := splash.New(c.Ctx(), options.Size, !isClientSSH)
 		c.outerLayers.NewLayer(
 			splashScreen.Ctx(),
@@ -303,11 +427,27 @@ func (c *Client) initialize(options ClientOptions) error {
 		screen.PositionTop,
 	)
 
+	// Colors are converted to the client's color profile only at the
+	// very end, so this covers both pane output and cy's own UI
+	rendererOptions := []renderer.RendererOption{
+		renderer.WithProfile(options.Profile),
+	}
+	if slices.Contains(
+		c.capabilities,
+		P.CapabilitySynchronizedOutput,
+	) {
+		rendererOptions = append(
+			rendererOptions,
+			renderer.WithSynchronizedOutput,
+		)
+	}
+
 	c.renderer = renderer.NewRenderer(
 		c.Ctx(),
 		info,
 		options.Size,
 		c.outerLayers,
+		rendererOptions...,
 	)
 
 	if isClientSSH {
@@ -355,7 +495,64 @@ func (c *Client) GetLayout() layout.Layout {
 // also updates the client's bindings and params to point to that node. If it
 // does not exist, the client uses the bindings and parameters of the root
 // node.
+//
+// The layout that was replaced is recorded in the client's layout history
+// unless the new layout is equivalent to it.
 func (c *Client) SetLayout(l layout.Layout) error {
+	previous := c.layoutEngine.Get()
+
+	err := c.setLayout(l)
+	if err != nil {
+		return err
+	}
+
+	if layout.IsEquivalent(
+		previous,
+		l,
+		c.params.LayoutHistoryIgnoreCosmetic(),
+	) {
+		return nil
+	}
+
+	c.Lock()
+	c.layoutHistory.Record(previous, c.params.LayoutHistory())
+	c.Unlock()
+	return nil
+}
+
+// UndoLayout restores the layout the client had before the most recent
+// change to it. It reports whether there was a change to undo.
+func (c *Client) UndoLayout() (bool, error) {
+	current := c.layoutEngine.Get()
+
+	c.Lock()
+	previous, ok := c.layoutHistory.Undo(current)
+	c.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	return true, c.setLayout(previous)
+}
+
+// RedoLayout reverses the most recent call to UndoLayout. It reports whether
+// there was a change to redo.
+func (c *Client) RedoLayout() (bool, error) {
+	current := c.layoutEngine.Get()
+
+	c.Lock()
+	next, ok := c.layoutHistory.Redo(current)
+	c.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	return true, c.setLayout(next)
+}
+
+func (c *Client) setLayout(l layout.Layout) error {
 	err := c.layoutEngine.Set(l)
 	if err != nil {
 		return err
@@ -391,7 +588,7 @@ func (c *Client) SetLayout(l layout.Layout) error {
 	// But their bindings still have to work.
 	if node == nil || !isPane || len(path) == 0 {
 		root := c.cy.tree.Root()
-		c.binds.SetScopes(root.Binds())
+		c.setScopes(root.Binds())
 		c.params.SetParent(root.Params())
 		return nil
 	}
@@ -402,12 +599,154 @@ func (c *Client) SetLayout(l layout.Layout) error {
 		scopes = append(scopes, pathNode.Binds())
 	}
 
-	c.binds.SetScopes(scopes...)
+	c.setScopes(scopes...)
 	c.params.SetParent(node.Params())
 	c.interact(c.cy.visits, node.Id())
 	return nil
 }
 
+// setScopes sets the scopes the client's bindings come from. Read-only
+// clients only ever use the :read-only scope. The caller must hold the lock
+// on the Client.
+func (c *Client) setScopes(scopes ...*bind.BindScope) {
+	if c.readOnly {
+		scopes = []*bind.BindScope{c.cy.readOnlyBinds}
+	}
+
+	c.binds.SetScopes(scopes...)
+}
+
+// ZoomLayout shows only the attached pane in the client's layout until the
+// client attaches to a different pane or UnzoomLayout is called.
+func (c *Client) ZoomLayout(keepDecorations bool) error {
+	return c.layoutEngine.Zoom(keepDecorations)
+}
+
+// UnzoomLayout restores the client's full layout.
+func (c *Client) UnzoomLayout() error {
+	return c.layoutEngine.Unzoom()
+}
+
+func (c *Client) IsLayoutZoomed() bool {
+	return c.layoutEngine.IsZoomed()
+}
+
+// LayoutPanes returns the panes in the client's layout that are visible on
+// the screen.
+func (c *Client) LayoutPanes() []engine.PaneRegion {
+	return c.layoutEngine.Panes()
+}
+
+// SetTiling sets the group whose panes the client's layout is tiled with
+// whenever panes are added to or removed from it. A nil tiling disables
+// automatic tiling.
+func (c *Client) SetTiling(tiling *api.Tiling) {
+	c.Lock()
+	defer c.Unlock()
+	c.tiling = tiling
+	c.tiledPanes = nil
+
+	if tiling == nil {
+		return
+	}
+
+	if group, ok := c.cy.tree.GroupById(tiling.Group); ok {
+		c.tiledPanes = getPaneIds(group)
+	}
+}
+
+func (c *Client) GetTiling() *api.Tiling {
+	c.RLock()
+	defer c.RUnlock()
+	return c.tiling
+}
+
+// getPaneIds gets the IDs of all of the panes inside of group.
+func getPaneIds(group *tree.Group) (ids []tree.NodeID) {
+	for _, leaf := range group.Leaves() {
+		ids = append(ids, leaf.Id())
+	}
+	return
+}
+
+// restoreLayout runs Janet code that restores a layout saved to disk, such as
+// the one most recently saved with layout/save.
+func (c *Client) restoreLayout(code string) {
+	err := c.execute(code)
+	if err == nil || err == context.Canceled {
+		return
+	}
+
+	msg := fmt.Sprintf(
+		"an error occurred while restoring the layout: %s",
+		err.Error(),
+	)
+	c.cy.log.Error().Msg(msg)
+	c.toast.Error(msg)
+}
+
+// tile replaces the client's layout with the result of layout/tile-group.
+func (c *Client) tile(tiling *api.Tiling) error {
+	value, err := c.cy.tileGroup.CallResult(
+		c.Ctx(),
+		c,
+		tiling.Group,
+		tiling.Strategy,
+	)
+	if err != nil {
+		return err
+	}
+	defer value.Free()
+
+	var l layout.Layout
+	err = value.Unmarshal(&l)
+	if err != nil {
+		return err
+	}
+
+	return c.SetLayout(l)
+}
+
+// retile tiles the client's layout again if the panes in the group it is
+// tiling have changed since it was last tiled.
+func (c *Client) retile() {
+	c.Lock()
+	tiling := c.tiling
+	if tiling == nil {
+		c.Unlock()
+		return
+	}
+
+	group, ok := c.cy.tree.GroupById(tiling.Group)
+	if !ok {
+		// The group was removed, so there is nothing left to tile
+		c.tiling = nil
+		c.tiledPanes = nil
+		c.Unlock()
+		return
+	}
+
+	panes := getPaneIds(group)
+	if len(panes) == 0 || slices.Equal(panes, c.tiledPanes) {
+		c.Unlock()
+		return
+	}
+	c.tiledPanes = panes
+	c.Unlock()
+
+	err := c.tile(tiling)
+	if err == nil || err == context.Canceled {
+		return
+	}
+
+	msg := fmt.Sprintf(
+		"an error occurred while tiling the layout: %s",
+		err.Error(),
+	)
+	c.cy.log.Error().Msg(msg)
+	c.toast.Error(msg)
+}
+
 // attach changes the tree node the client is currently attached to in their
 // layout.
 func (c *Client) attach(node tree.Node) error {
@@ -499,6 +838,12 @@ func (c *Client) Get(key string) (value interface{}, ok bool) {
 	return c.params.Get(key)
 }
 
+func (c *Client) Env() map[string]string {
+	c.RLock()
+	defer c.RUnlock()
+	return maps.Clone(c.env)
+}
+
 func (c *Client) Params() *params.Parameters {
 	return c.params
 }
@@ -527,6 +872,62 @@ func (c *Client) Detach() {
 	c.Cancel()
 }
 
+// Capabilities returns the features of the client's terminal that cy
+// supports.
+func (c *Client) Capabilities() []P.Capability {
+	c.RLock()
+	defer c.RUnlock()
+	return c.capabilities
+}
+
+// profileKeywords maps each terminal color profile to the keyword used to
+// represent it in Janet.
+var profileKeywords = map[termenv.Profile]janet.Keyword{
+	termenv.Ascii:     "ascii",
+	termenv.ANSI:      "ansi",
+	termenv.ANSI256:   "ansi256",
+	termenv.TrueColor: "truecolor",
+}
+
+func (c *Client) Info() api.ClientInfo {
+	c.RLock()
+	defer c.RUnlock()
+
+	info := api.ClientInfo{
+		ID:      c.id,
+		Size:    c.size,
+		Profile: profileKeywords[c.profile],
+		Connected: float64(
+			c.Started().UnixNano(),
+		) / float64(time.Second),
+		SSH:       isSSH(c.env),
+		ReadOnly:  c.readOnly,
+		User:      c.user.Name,
+		UID:       c.user.UID,
+		Access:    accessKeywords[c.user.Access],
+		Suspended: c.expiry != nil,
+	}
+
+	for _, capability := range c.capabilities {
+		info.Capabilities = append(
+			info.Capabilities,
+			janet.Keyword(capability),
+		)
+	}
+
+	if c.leader != nil {
+		id := c.leader.id
+		info.Following = &id
+	}
+
+	if c.node != nil {
+		id := c.node.Id()
+		info.Node = &id
+	}
+
+	return info
+}
+
 // execute runs some Janet code on behalf of the client.
 func (c *Client) execute(code string) error {
 	_, err := c.cy.ExecuteCall(c.Ctx(), c, janet.Call{
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"os"
 
 	"github.com/cfoust/cy/pkg/bind"
 	"github.com/cfoust/cy/pkg/geom"
@@ -15,24 +16,128 @@ func New(
 	options stream.CmdOptions,
 	dataDir string,
 	timeBinds, copyBinds *bind.BindScope,
+) (*replay.Replayable, error) {
+	return Resume(
+		ctx,
+		options,
+		dataDir,
+		"",
+		nil,
+		timeBinds,
+		copyBinds,
+	)
+}
+
+// Resume starts a new command just like New, but the Replayable it returns
+// begins with the history of a command that ran in a previous cy server. If
+// `recording` is the path to that command's .borg file, its history is read
+// from there and the new command's output is appended to it. Otherwise the
+// Replayable begins with `history`, which is also written to the new
+// recording so that it is not lost when cy restarts again.
+func Resume(
+	ctx context.Context,
+	options stream.CmdOptions,
+	dataDir string,
+	recording string,
+	history []sessions.Event,
+	timeBinds, copyBinds *bind.BindScope,
 ) (*replay.Replayable, error) {
 	cmd, err := stream.NewCmd(ctx, options, geom.DEFAULT_SIZE)
 	if err != nil {
 		return nil, err
 	}
 
+	return wrap(
+		ctx,
+		cmd,
+		dataDir,
+		recording,
+		history,
+		timeBinds,
+		copyBinds,
+	)
+}
+
+// Adopt is the same as Resume, but rather than starting a new command, it
+// takes over a command that was started by another cy server, which is
+// running in the pseudo-terminal `ptmx` with process ID `pid`.
+func Adopt(
+	ctx context.Context,
+	options stream.CmdOptions,
+	ptmx *os.File,
+	pid int,
+	dataDir string,
+	recording string,
+	history []sessions.Event,
+	timeBinds, copyBinds *bind.BindScope,
+) (*replay.Replayable, error) {
+	cmd, err := stream.AdoptCmd(
+		ctx,
+		options,
+		geom.DEFAULT_SIZE,
+		ptmx,
+		pid,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return wrap(
+		ctx,
+		cmd,
+		dataDir,
+		recording,
+		history,
+		timeBinds,
+		copyBinds,
+	)
+}
+
+// wrap creates a Replayable for `cmd` that records its output to
+// `recording`, if it is set, or to a new file in `dataDir`, if that is set.
+func wrap(
+	ctx context.Context,
+	cmd *stream.Cmd,
+	dataDir string,
+	recording string,
+	history []sessions.Event,
+	timeBinds, copyBinds *bind.BindScope,
+) (*replay.Replayable, error) {
+	// If the old recording can't be reopened, the command's output is
+	// recorded to a new file instead
+	if len(recording) > 0 {
+		recorder, events, err := sessions.OpenFileRecorder(
+			ctx,
+			recording,
+		)
+		if err == nil {
+			return replay.NewReplayableFrom(
+				ctx,
+				cmd,
+				sessions.NewEventStream(cmd, recorder),
+				events,
+				timeBinds,
+				copyBinds,
+			), nil
+		}
+	}
+
 	if len(dataDir) == 0 {
-		replayable := replay.NewReplayable(
+		replayable := replay.NewReplayableFrom(
 			ctx,
 			cmd,
 			cmd,
+			history,
 			timeBinds,
 			copyBinds,
 		)
 		return replayable, nil
 	}
 
-	borgPath, err := sessions.GetFilename(dataDir, options.Directory)
+	borgPath, err := sessions.GetFilename(
+		dataDir,
+		cmd.Options().Directory,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -42,11 +147,39 @@ func New(
 		return nil, err
 	}
 
-	return replay.NewReplayable(
+	for _, event := range history {
+		recorder.Process(event)
+	}
+
+	return replay.NewReplayableFrom(
 		ctx,
 		cmd,
 		sessions.NewEventStream(cmd, recorder),
+		history,
 		timeBinds,
 		copyBinds,
 	), nil
 }
+
+// Recorder returns the FileRecorder to which the output of the command in
+// `r` is being recorded, if there is one.
+func Recorder(r *replay.Replayable) (*sessions.FileRecorder, bool) {
+	eventStream, ok := r.Stream().(*sessions.EventStream)
+	if !ok {
+		return nil, false
+	}
+
+	recorder, ok := eventStream.Handler().(*sessions.FileRecorder)
+	return recorder, ok
+}
+
+// Recording returns the path to the file to which the output of the command
+// in `r` is being recorded, if there is one.
+func Recording(r *replay.Replayable) (filename string, ok bool) {
+	recorder, ok := Recorder(r)
+	if !ok {
+		return "", false
+	}
+
+	return recorder.Filename(), true
+}
@@ -2,14 +2,29 @@ package cy
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cfoust/cy/pkg/cy/cmd"
 	"github.com/cfoust/cy/pkg/geom"
+	P "github.com/cfoust/cy/pkg/io/protocol"
+	"github.com/cfoust/cy/pkg/janet"
+	L "github.com/cfoust/cy/pkg/layout"
 	T "github.com/cfoust/cy/pkg/mux/screen/tree"
 	"github.com/cfoust/cy/pkg/mux/stream"
+	"github.com/cfoust/cy/pkg/params"
 	"github.com/cfoust/cy/pkg/replay"
+	"github.com/cfoust/cy/pkg/sessions"
+	"github.com/cfoust/cy/pkg/taro"
 
+	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/require"
 )
 
@@ -118,3 +133,671 @@ func TestDefaultShell(t *testing.T) {
 	options := cmd.Options()
 	require.Equal(t, "/bin/zsh", options.Command)
 }
+
+// outputOf gets all of the output in a list of events.
+func outputOf(events []sessions.Event) string {
+	var output []byte
+	for _, event := range events {
+		if msg, ok := event.Message.(P.OutputMessage); ok {
+			output = append(output, msg.Data...)
+		}
+	}
+	return string(output)
+}
+
+// recordedOutput gets all of the output in the recording of the command
+// running in pane.
+func recordedOutput(t *testing.T, pane *T.Pane) string {
+	r, ok := pane.Screen().(*replay.Replayable)
+	require.True(t, ok)
+	recording, ok := cmd.Recording(r)
+	require.True(t, ok)
+
+	events, err := sessions.ReadFile(recording)
+	require.NoError(t, err)
+	return outputOf(events)
+}
+
+// borgFiles gets the paths of all of the recordings in dataDir.
+func borgFiles(t *testing.T, dataDir string) []string {
+	var files []string
+	err := filepath.WalkDir(dataDir, func(
+		path string,
+		d fs.DirEntry,
+		err error,
+	) error {
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(path) == ".borg" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func TestResurrect(t *testing.T) {
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "data")
+
+	// Resurrection is off by default
+	config := filepath.Join(dir, "cyrc.janet")
+	require.NoError(t, os.WriteFile(
+		config,
+		[]byte(`(param/set :root :resurrect true)`),
+		0600,
+	))
+
+	start := func() (*Cy, *Client) {
+		ctx := context.Background()
+		cy, err := Start(ctx, Options{
+			Config:  config,
+			DataDir: dataDir,
+			Shell:   "/bin/bash",
+		})
+		require.NoError(t, err)
+
+		client, err := cy.NewClient(ctx, ClientOptions{
+			Env: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			Size: geom.DEFAULT_SIZE,
+		})
+		require.NoError(t, err)
+		return cy, client
+	}
+
+	server, client := start()
+	require.NoError(t, client.execute(`
+(def group (group/mkdir :root "/projects"))
+(param/set group :resurrect-test "value")
+(def pane (cmd/new group
+                   :command "/bin/sh"
+                   :args ["-c" "echo resurrected; sleep 100"]
+                   :name "test"))
+(pane/attach pane)
+`))
+
+	pane := client.Node().(*T.Pane)
+	require.Eventually(t, func() bool {
+		return strings.Contains(
+			recordedOutput(t, pane),
+			"resurrected",
+		)
+	}, 5*time.Second, 50*time.Millisecond)
+
+	recording, ok := cmd.Recording(pane.Screen().(*replay.Replayable))
+	require.True(t, ok)
+	recordings := borgFiles(t, dataDir)
+
+	require.NoError(t, server.saveSnapshot(server.Ctx()))
+	server.Cancel()
+
+	server, client = start()
+
+	projects, ok := server.tree.Root().ChildByName("projects")
+	require.True(t, ok)
+	value, ok := projects.Params().Get("resurrect-test")
+	require.True(t, ok)
+	var str string
+	require.NoError(t, value.(*janet.Value).Unmarshal(&str))
+	require.Equal(t, "value", str)
+
+	node, ok := projects.(*T.Group).ChildByName("test")
+	require.True(t, ok)
+	pane, ok = node.(*T.Pane)
+	require.True(t, ok)
+
+	// The output from before the restart is still there, and it's in
+	// the same recording
+	require.Eventually(t, func() bool {
+		return strings.Count(
+			recordedOutput(t, pane),
+			"resurrected",
+		) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+	newRecording, ok := cmd.Recording(pane.Screen().(*replay.Replayable))
+	require.True(t, ok)
+	require.Equal(t, recording, newRecording)
+
+	// No new recordings were created
+	require.ElementsMatch(t, recordings, borgFiles(t, dataDir))
+
+	// The client's layout now refers to the new pane
+	attached := L.Attached(client.layoutEngine.Get())
+	require.NotNil(t, attached)
+	require.Equal(t, pane.Id(), *attached)
+
+	// ...so the client did not need a new shell
+	shells, ok := server.tree.Root().ChildByName("shells")
+	require.True(t, ok)
+	require.Len(t, shells.(*T.Group).Children(), 1)
+}
+
+func TestRestoreLayout(t *testing.T) {
+	ctx := context.Background()
+	server, err := Start(ctx, Options{
+		DataDir: filepath.Join(t.TempDir(), "data"),
+		Shell:   "/bin/bash",
+	})
+	require.NoError(t, err)
+
+	connect := func() *Client {
+		client, err := server.NewClient(ctx, ClientOptions{
+			Env: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			Size: geom.DEFAULT_SIZE,
+		})
+		require.NoError(t, err)
+		return client
+	}
+
+	client := connect()
+	require.NoError(t, client.execute(`
+(param/set :root :restore-layout true)
+(def group (group/mkdir :root "/projects"))
+(layout/save "test" (layout/new
+                      (split
+                        (attach :id (cmd/new group))
+                        (pane :id (cmd/new group)))))
+(tree/kill (pane/current))
+`))
+
+	shells, ok := server.tree.Root().ChildByName("shells")
+	require.True(t, ok)
+	require.Len(t, shells.(*T.Group).Children(), 0)
+
+	// The restored layout is used instead of a new shell
+	client = connect()
+	require.Len(t, client.LayoutPanes(), 2)
+	require.Len(t, shells.(*T.Group).Children(), 0)
+}
+
+func TestHandoff(t *testing.T) {
+	start := func(handoff *Handoff) (*Cy, *Client) {
+		ctx := context.Background()
+		cy, err := Start(ctx, Options{
+			Shell:   "/bin/bash",
+			Handoff: handoff,
+		})
+		require.NoError(t, err)
+
+		client, err := cy.NewClient(ctx, ClientOptions{
+			Env: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			Size: geom.DEFAULT_SIZE,
+		})
+		require.NoError(t, err)
+		return cy, client
+	}
+
+	old, client := start(nil)
+	require.NoError(t, client.execute(`
+(def pane (cmd/new :root
+                   :command "/bin/sh"
+                   :args ["-c" "echo before; while read line; do echo got $line; done"]
+                   :name "test"))
+(pane/attach pane)
+(cmd/new :root
+         :command "/bin/sh"
+         :args ["-c" "i=0; while true; do i=$((i+1)); echo n$i; sleep 0.005; done"]
+         :name "counter")
+`))
+
+	id := client.Node().Id()
+	oldReplayable := client.Node().(*T.Pane).Screen().(*replay.Replayable)
+	require.Eventually(t, func() bool {
+		return strings.Contains(
+			outputOf(oldReplayable.Events()),
+			"before",
+		)
+	}, 5*time.Second, 50*time.Millisecond)
+
+	handoff, err := old.Handoff()
+	require.NoError(t, err)
+	// The initial shell and the panes we created
+	require.Len(t, handoff.Files, 3)
+
+	// The counter keeps printing while the new server starts
+	time.Sleep(100 * time.Millisecond)
+	handoff.Release()
+	old.Cancel()
+
+	server, client := start(handoff)
+	defer server.Cancel()
+
+	// The pane keeps its ID and history
+	node, ok := server.tree.NodeById(id)
+	require.True(t, ok)
+	require.Equal(t, "test", node.Name())
+	r := node.(*T.Pane).Screen().(*replay.Replayable)
+	require.Contains(t, outputOf(r.Events()), "before")
+
+	attached := L.Attached(client.layoutEngine.Get())
+	require.NotNil(t, attached)
+	require.Equal(t, id, *attached)
+
+	// ...and the original process is still running
+	require.Eventually(t, func() bool {
+		r.Cmd().Write([]byte("hello\n"))
+		return strings.Contains(outputOf(r.Events()), "got hello")
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// None of the output printed during the upgrade was lost or read
+	// twice
+	counter, ok := server.tree.Root().ChildByName("counter")
+	require.True(t, ok)
+	counterReplayable := counter.(*T.Pane).Screen().(*replay.Replayable)
+	var lines []string
+	require.Eventually(t, func() bool {
+		lines = strings.Fields(outputOf(counterReplayable.Events()))
+		return len(lines) > 100
+	}, 5*time.Second, 50*time.Millisecond)
+	for i, line := range lines[:100] {
+		require.Equal(t, fmt.Sprintf("n%d", i+1), line)
+	}
+}
+
+func TestHandoffRecording(t *testing.T) {
+	dataDir := filepath.Join(t.TempDir(), "data")
+	start := func(handoff *Handoff) (*Cy, *Client) {
+		ctx := context.Background()
+		cy, err := Start(ctx, Options{
+			DataDir: dataDir,
+			Shell:   "/bin/bash",
+			Handoff: handoff,
+		})
+		require.NoError(t, err)
+
+		client, err := cy.NewClient(ctx, ClientOptions{
+			Env: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			Size: geom.DEFAULT_SIZE,
+		})
+		require.NoError(t, err)
+		return cy, client
+	}
+
+	old, client := start(nil)
+	require.NoError(t, client.execute(`
+(def pane (cmd/new :root
+                   :command "/bin/sh"
+                   :args ["-c" "echo before; while read line; do echo got $line; done"]
+                   :name "test"))
+(pane/attach pane)
+`))
+
+	id := client.Node().Id()
+	pane := client.Node().(*T.Pane)
+	require.Eventually(t, func() bool {
+		return strings.Contains(recordedOutput(t, pane), "before")
+	}, 5*time.Second, 50*time.Millisecond)
+
+	recording, ok := cmd.Recording(pane.Screen().(*replay.Replayable))
+	require.True(t, ok)
+	recordings := borgFiles(t, dataDir)
+
+	handoff, err := old.Handoff()
+	require.NoError(t, err)
+	handoff.Release()
+	old.Cancel()
+
+	server, _ := start(handoff)
+	defer server.Cancel()
+
+	node, ok := server.tree.NodeById(id)
+	require.True(t, ok)
+	pane = node.(*T.Pane)
+	r := pane.Screen().(*replay.Replayable)
+
+	// The new server keeps writing to the same recording
+	newRecording, ok := cmd.Recording(r)
+	require.True(t, ok)
+	require.Equal(t, recording, newRecording)
+	require.Eventually(t, func() bool {
+		r.Cmd().Write([]byte("hello\n"))
+		return strings.Contains(recordedOutput(t, pane), "got hello")
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Equal(t, 1, strings.Count(recordedOutput(t, pane), "before"))
+
+	// No new recordings were created
+	require.ElementsMatch(t, recordings, borgFiles(t, dataDir))
+}
+
+func TestReadOnly(t *testing.T) {
+	server, create := setup(t)
+	writer := create(geom.DEFAULT_SIZE)
+
+	client, err := server.NewClient(context.Background(), ClientOptions{
+		Env: map[string]string{
+			"TERM": "xterm-256color",
+		},
+		Size:     geom.DEFAULT_SIZE,
+		ReadOnly: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, writer.Node(), client.Node())
+
+	// Only the :read-only bindings apply
+	scopes := client.binds.Scopes()
+	require.Len(t, scopes, 1)
+	require.Equal(t, server.readOnlyBinds, scopes[0])
+	require.False(t, client.canSend())
+
+	r := client.Node().(*T.Pane).Screen().(*replay.Replayable)
+	r.EnterReplay()
+	require.True(t, client.canSend())
+}
+
+func TestFollowOnScroll(t *testing.T) {
+	_, create := setup(t)
+	client := create(geom.DEFAULT_SIZE)
+
+	// isFollowing scrolls up in a new pane and reports whether replay
+	// mode shows that it is following new output
+	isFollowing := func() bool {
+		require.NoError(t, client.execute(`
+(pane/attach (cmd/new :root))
+`))
+		r := client.Node().(*T.Pane).Screen().(*replay.Replayable)
+		r.Send(taro.MouseMsg{
+			Type:   taro.MousePress,
+			Button: taro.MouseWheelUp,
+			Down:   true,
+		})
+		require.True(t, r.IsReplayMode())
+
+		var screen string
+		require.Eventually(t, func() bool {
+			screen = ""
+			for _, line := range r.State().Image {
+				screen += line.String()
+			}
+			return strings.Contains(screen, "⏵") ||
+				strings.Contains(screen, "FOLLOW")
+		}, 5*time.Second, 50*time.Millisecond)
+		return strings.Contains(screen, "FOLLOW")
+	}
+
+	require.False(t, isFollowing())
+
+	require.NoError(t, client.execute(`
+(param/set :root :replay-follow-on-scroll true)
+`))
+	require.True(t, isFollowing())
+}
+
+func TestFollow(t *testing.T) {
+	_, create := setup(t)
+	leader := create(geom.DEFAULT_SIZE)
+	follower := create(geom.DEFAULT_SIZE)
+
+	require.Error(t, follower.Follow(follower.id))
+	require.NoError(t, follower.Follow(leader.id))
+	require.Error(t, leader.Follow(follower.id))
+
+	require.NoError(t, leader.execute(`
+(pane/attach (cmd/new :root))
+`))
+	require.Eventually(t, func() bool {
+		return follower.Node() == leader.Node()
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// matches reports whether the follower mirrors the leader's layout,
+	// including the attached pane and the active tab
+	matches := func() bool {
+		return reflect.DeepEqual(
+			follower.GetLayout().Root,
+			leader.GetLayout().Root,
+		) && follower.Node() == leader.Node()
+	}
+
+	require.NoError(t, leader.execute(`
+(layout/set (layout/new
+              (tabs @[(active-tab "a" (split
+                                        (attach :id (cmd/new :root))
+                                        (pane :id (cmd/new :root))))
+                      (tab "b" (pane :id (cmd/new :root)))])))
+`))
+	require.Eventually(t, matches, 5*time.Second, 50*time.Millisecond)
+	first := leader.Node()
+
+	// Moving within the split
+	require.NoError(t, leader.execute(`(action/move-right)`))
+	require.NotEqual(t, first, leader.Node())
+	require.Eventually(t, matches, 5*time.Second, 50*time.Millisecond)
+	second := leader.Node()
+
+	// Switching tabs
+	require.NoError(t, leader.execute(`(action/next-tab)`))
+	require.NotEqual(t, second, leader.Node())
+	require.Eventually(t, matches, 5*time.Second, 50*time.Millisecond)
+
+	info := follower.Info()
+	require.NotNil(t, info.Following)
+	require.Equal(t, leader.id, *info.Following)
+
+	leader.Cancel()
+	require.Eventually(t, func() bool {
+		return follower.Leader() == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestAutoTile(t *testing.T) {
+	server, create := setup(t)
+	client := create(geom.DEFAULT_SIZE)
+
+	group := server.tree.Root().NewGroup()
+	require.NoError(t, client.execute(fmt.Sprintf(`
+(layout/auto-tile %d :even-horizontal)
+(cmd/new %d)
+(cmd/new %d)
+`, group.Id(), group.Id(), group.Id())))
+
+	// New panes in the group are added to the layout
+	require.Eventually(t, func() bool {
+		return len(client.LayoutPanes()) == 2
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestAuthorize(t *testing.T) {
+	server, _ := setup(t)
+	root := server.tree.Root().Params()
+
+	// The uid of a user that is neither root nor the owner
+	uid := os.Getuid() + 1000
+
+	user, err := server.Authorize(os.Getuid())
+	require.NoError(t, err)
+	require.Equal(t, AccessFull, user.Access)
+
+	user, err = server.Authorize(uid)
+	require.NoError(t, err)
+	require.Equal(t, AccessNone, user.Access)
+	require.False(t, server.IsShared())
+
+	require.NoError(t, root.Set(params.ParamSocketUsers, []string{
+		fmt.Sprintf("%d:read-only", uid),
+		fmt.Sprintf("%d", uid+1),
+		fmt.Sprintf("%d:blah", uid+2),
+	}))
+	require.True(t, server.IsShared())
+
+	user, err = server.Authorize(uid)
+	require.NoError(t, err)
+	require.Equal(t, AccessReadOnly, user.Access)
+
+	user, err = server.Authorize(uid + 1)
+	require.NoError(t, err)
+	require.Equal(t, AccessFull, user.Access)
+
+	_, err = server.Authorize(uid + 2)
+	require.Error(t, err)
+
+	// Read-only users can only connect read-only clients
+	client, err := server.NewUserClient(
+		context.Background(),
+		ClientOptions{
+			Env: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			Size: geom.DEFAULT_SIZE,
+		},
+		User{UID: uid, Name: "test", Access: AccessReadOnly},
+	)
+	require.NoError(t, err)
+	require.True(t, client.Info().ReadOnly)
+	require.Equal(t, "test", client.Info().User)
+
+	_, err = server.NewUserClient(
+		context.Background(),
+		ClientOptions{Size: geom.DEFAULT_SIZE},
+		User{UID: uid, Name: "test", Access: AccessReplayOnly},
+	)
+	require.Error(t, err)
+}
+
+func TestResume(t *testing.T) {
+	server, _ := setup(t)
+	owner := server.Owner()
+
+	newClient := func(token string) *Client {
+		client, err := server.NewClient(
+			server.Ctx(),
+			ClientOptions{
+				Env: map[string]string{
+					"TERM": "xterm-256color",
+				},
+				Size:  geom.DEFAULT_SIZE,
+				Token: token,
+			},
+		)
+		require.NoError(t, err)
+
+		// Resuming redraws the client's screen, which blocks if
+		// nothing reads its output
+		go io.Copy(io.Discard, client)
+		return client
+	}
+
+	// Clients without a token cannot be resumed
+	client := newClient("")
+	client.Suspend()
+	require.Error(t, client.Ctx().Err())
+
+	resume := func(token string, user User) (*Client, bool) {
+		return server.ResumeClient(ClientOptions{
+			Env: map[string]string{
+				"TERM":          "xterm-256color",
+				"SSH_AUTH_SOCK": "/tmp/agent",
+			},
+			Size:    geom.Vec2{R: 20, C: 60},
+			Profile: termenv.ANSI256,
+			Token:   token,
+		}, user)
+	}
+
+	client = newClient("token")
+	_, ok := resume("token", owner)
+	require.False(t, ok)
+
+	client.Suspend()
+	require.True(t, client.IsSuspended())
+	require.True(t, client.Info().Suspended)
+
+	other := owner
+	other.UID++
+	_, ok = resume("token", other)
+	require.False(t, ok)
+	_, ok = resume("wrong", owner)
+	require.False(t, ok)
+
+	resumed, ok := resume("token", owner)
+	require.True(t, ok)
+	require.Equal(t, client, resumed)
+	require.False(t, client.IsSuspended())
+	require.NoError(t, client.Ctx().Err())
+
+	// The client takes on the environment, size, and color profile of
+	// the terminal it was resumed from
+	require.Equal(t, "/tmp/agent", client.Env()["SSH_AUTH_SOCK"])
+	info := client.Info()
+	require.Equal(t, geom.Vec2{R: 20, C: 60}, info.Size)
+	require.Equal(t, "ansi256", string(info.Profile))
+	require.NoError(t, client.execute(`(shell/attach)`))
+	r, ok := client.Node().(*T.Pane).Screen().(*replay.Replayable)
+	require.True(t, ok)
+	cmd, ok := r.Cmd().(*stream.Cmd)
+	require.True(t, ok)
+	require.Equal(t, "/tmp/agent", cmd.Options().Env["SSH_AUTH_SOCK"])
+
+	// Clients are removed immediately if :reconnect-timeout is 0
+	server.tree.Root().Params().SetReconnectTimeout(0)
+	client.Suspend()
+	require.Error(t, client.Ctx().Err())
+}
+
+func TestCapabilities(t *testing.T) {
+	server, _ := setup(t)
+
+	client, err := server.NewClient(
+		server.Ctx(),
+		ClientOptions{
+			Env: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			Size: geom.DEFAULT_SIZE,
+			Capabilities: []P.Capability{
+				"unknown",
+				P.CapabilitySynchronizedOutput,
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]P.Capability{P.CapabilitySynchronizedOutput},
+		client.Capabilities(),
+	)
+	require.Equal(
+		t,
+		[]janet.Keyword{"synchronized-output"},
+		client.Info().Capabilities,
+	)
+}
+
+func TestUpdateEnvironment(t *testing.T) {
+	server, _ := setup(t)
+
+	client, err := server.NewClient(context.Background(), ClientOptions{
+		Env: map[string]string{
+			"TERM":          "xterm-256color",
+			"SSH_AUTH_SOCK": "/tmp/agent",
+			"FOO":           "bar",
+		},
+		Size: geom.DEFAULT_SIZE,
+	})
+	require.NoError(t, err)
+
+	env := func() map[string]string {
+		r, ok := client.Node().(*T.Pane).Screen().(*replay.Replayable)
+		require.True(t, ok)
+		cmd, ok := r.Cmd().(*stream.Cmd)
+		require.True(t, ok)
+		return cmd.Options().Env
+	}
+
+	require.Equal(t, "/tmp/agent", env()["SSH_AUTH_SOCK"])
+	require.NotContains(t, env(), "FOO")
+	require.Contains(t, env(), "CY")
+
+	client.Params().Set(params.ParamUpdateEnvironment, []string{"FOO"})
+	require.NoError(t, client.execute(`(shell/attach)`))
+	require.Equal(t, "bar", env()["FOO"])
+	require.NotContains(t, env(), "SSH_AUTH_SOCK")
+}
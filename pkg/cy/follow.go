@@ -0,0 +1,111 @@
+package cy
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/cfoust/cy/pkg/layout"
+	"github.com/cfoust/cy/pkg/mux/screen/toasts"
+	"github.com/cfoust/cy/pkg/util"
+)
+
+// Follow makes the client mirror the layout (and thus the attached pane) of
+// the client with the given ID until that client disconnects or Unfollow is
+// called.
+func (c *Client) Follow(id ClientID) error {
+	leader, ok := c.cy.getClient(id)
+	if !ok {
+		return fmt.Errorf("client %d not found", id)
+	}
+
+	// Following a client that is itself following this one would never
+	// settle on a layout
+	for other := leader; other != nil; other = other.Leader() {
+		if other == c {
+			return fmt.Errorf(
+				"client %d cannot follow client %d",
+				c.id,
+				id,
+			)
+		}
+	}
+
+	c.Unfollow()
+
+	lifetime := util.NewLifetime(c.Ctx())
+	c.Lock()
+	c.leader = leader
+	c.following = &lifetime
+	c.Unlock()
+
+	// The layout is mirrored once before returning so that the client is
+	// attached to the leader's pane immediately
+	c.mirror(leader)
+	go c.pollLeader(lifetime, leader)
+	return nil
+}
+
+// Unfollow stops the client from following another client. The client
+// keeps the layout it had most recently mirrored.
+func (c *Client) Unfollow() {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.following != nil {
+		c.following.Cancel()
+	}
+
+	c.leader = nil
+	c.following = nil
+}
+
+// Leader returns the client this client is following, if any.
+func (c *Client) Leader() *Client {
+	c.RLock()
+	defer c.RUnlock()
+	return c.leader
+}
+
+// mirror sets the client's layout to the layout of `leader` if it differs.
+// Unlike layout.IsEquivalent, this considers the attached pane and the active
+// tab, since following those is the point of following another client.
+func (c *Client) mirror(leader *Client) {
+	l := leader.GetLayout()
+	if reflect.DeepEqual(c.GetLayout().Root, l.Root) {
+		return
+	}
+
+	err := c.setLayout(layout.New(layout.Copy(l.Root)))
+	if err != nil {
+		c.cy.log.Error().Err(err).Msg("failed to mirror layout")
+	}
+}
+
+func (c *Client) pollLeader(lifetime util.Lifetime, leader *Client) {
+	updates := leader.layoutEngine.Subscribe(lifetime.Ctx())
+
+	for {
+		select {
+		case <-lifetime.Ctx().Done():
+			return
+		case <-leader.Ctx().Done():
+			c.Lock()
+			if c.leader == leader {
+				c.leader = nil
+				c.following = nil
+			}
+			c.Unlock()
+			lifetime.Cancel()
+
+			c.toast.Send(toasts.Toast{
+				Message: fmt.Sprintf(
+					"client %d disconnected, no longer following",
+					leader.id,
+				),
+			})
+			return
+		case <-updates.Recv():
+			c.mirror(leader)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package cy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cfoust/cy/pkg/cy/cmd"
+	"github.com/cfoust/cy/pkg/mux/stream"
+	"github.com/cfoust/cy/pkg/replay"
+	"github.com/cfoust/cy/pkg/sessions"
+)
+
+// Handoff is everything a cy server passes to the server that replaces it
+// during an upgrade, which lets the new server take over the processes that
+// are running in the old server's panes rather than starting them again.
+type Handoff struct {
+	// The serialized state of the server, which is a snapshot with some
+	// extra information about each process.
+	State []byte
+	// The pseudo-terminals of every process, in the order that State
+	// refers to them.
+	Files []*os.File
+
+	cmds      []*stream.Cmd
+	recorders []*sessions.FileRecorder
+	events    []string
+}
+
+// HANDOFF_PAUSE_TIMEOUT is how long we wait for a pane to finish handling the
+// output it has already read before we give up on handing it off.
+const HANDOFF_PAUSE_TIMEOUT = time.Second
+
+// add adds the process running in `command` to the Handoff. `command` stops
+// reading from its pseudo-terminal so that no output is lost between
+// capturing the pane's history and the new server taking over the
+// pseudo-terminal.
+func (h *Handoff) add(
+	ctx context.Context,
+	r *replay.Replayable,
+	command *stream.Cmd,
+	saved *snapshotCmd,
+) (err error) {
+	ptmx, pid, err := command.PTY()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, HANDOFF_PAUSE_TIMEOUT)
+	defer cancel()
+
+	err = command.Pause(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			command.Resume()
+		}
+	}()
+
+	// If the pane is being recorded, the new server appends to the same
+	// recording, so we just make sure that all of the pane's output has
+	// been written to it
+	if recorder, ok := cmd.Recorder(r); ok && len(saved.Recording) > 0 {
+		err = recorder.Close()
+		if err != nil {
+			recorder.Reopen()
+			return err
+		}
+
+		saved.PID = pid
+		saved.PTY = len(h.Files)
+		h.Files = append(h.Files, ptmx)
+		h.cmds = append(h.cmds, command)
+		h.recorders = append(h.recorders, recorder)
+		return nil
+	}
+
+	// The new server can't read the old server's memory, so we write the
+	// pane's history to a temporary file
+	file, err := os.CreateTemp("", "cy-handoff-*.borg")
+	if err != nil {
+		return err
+	}
+	file.Close()
+
+	writer, err := sessions.Create(file.Name())
+	if err != nil {
+		return err
+	}
+
+	for _, event := range r.Events() {
+		err = writer.Write(event)
+		if err != nil {
+			writer.Close()
+			return err
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	saved.PID = pid
+	saved.PTY = len(h.Files)
+	saved.Events = file.Name()
+	h.Files = append(h.Files, ptmx)
+	h.cmds = append(h.cmds, command)
+	h.events = append(h.events, file.Name())
+	return nil
+}
+
+// Discard removes the temporary files created for the Handoff and lets the
+// server read from and record the processes in it again. It should be called
+// if the Handoff could not be passed to a new server.
+func (h *Handoff) Discard() {
+	for _, recorder := range h.recorders {
+		recorder.Reopen()
+	}
+
+	for _, cmd := range h.cmds {
+		cmd.Resume()
+	}
+
+	for _, filename := range h.events {
+		os.Remove(filename)
+	}
+}
+
+// Release makes the server give up control of all of the processes in the
+// Handoff. It should only be called once the new server has received them.
+func (h *Handoff) Release() {
+	for _, cmd := range h.cmds {
+		cmd.Release()
+	}
+}
+
+// Handoff captures the state of the server and all of the processes running
+// in its panes so that they can be passed to a new server, which should call
+// Start with Options.Handoff set to the result. The processes are still
+// controlled by this server until (*Handoff).Release is called, but it stops
+// reading their output until (*Handoff).Discard is called.
+func (c *Cy) Handoff() (*Handoff, error) {
+	handoff := &Handoff{}
+
+	data, err := json.Marshal(c.takeSnapshot(c.Ctx(), handoff))
+	if err != nil {
+		handoff.Discard()
+		return nil, err
+	}
+
+	handoff.State = data
+	return handoff, nil
+}
+
+// adopt takes over the processes in a Handoff from another server.
+func (c *Cy) adopt(handoff *Handoff) error {
+	var saved snapshot
+	err := json.Unmarshal(handoff.State, &saved)
+	if err != nil {
+		return fmt.Errorf("invalid handoff: %s", err)
+	}
+
+	return c.restoreSnapshot(c.Ctx(), saved, handoff.Files)
+}
@@ -27,16 +27,18 @@ func (c *Cy) initJanet(ctx context.Context) (*janet.VM, error) {
 			TimeBinds: c.timeBinds,
 			CopyBinds: c.copyBinds,
 		},
+		"client": &api.ClientModule{Server: c},
 		"cy":     &CyModule{cy: c},
 		"exec":   &api.ExecModule{Server: c},
 		"group":  &api.GroupModule{Tree: c.tree},
 		"input":  &api.InputModule{Tree: c.tree, Server: c.muxServer},
-		"layout": &api.LayoutModule{},
+		"layout": &api.LayoutModule{Tree: c.tree},
 		"msg":    &api.MsgModule{Server: c},
 		"key": &api.KeyModule{
-			Tree:      c.tree,
-			TimeBinds: c.timeBinds,
-			CopyBinds: c.copyBinds,
+			Tree:          c.tree,
+			TimeBinds:     c.timeBinds,
+			CopyBinds:     c.copyBinds,
+			ReadOnlyBinds: c.readOnlyBinds,
 		},
 		"pane":  &api.PaneModule{Tree: c.tree},
 		"param": &api.ParamModule{Tree: c.tree},
@@ -90,5 +92,36 @@ func (c *Cy) initJanet(ctx context.Context) (*janet.VM, error) {
 		}
 	}
 
+	c.tileGroup, err = lookupFunction(ctx, vm, "layout/tile-group")
+	if err != nil {
+		return nil, err
+	}
+
 	return vm, nil
 }
+
+// lookupFunction gets the Janet function bound to the symbol `name`.
+func lookupFunction(
+	ctx context.Context,
+	vm *janet.VM,
+	name string,
+) (*janet.Function, error) {
+	result, err := vm.ExecuteCall(ctx, nil, janet.Call{
+		Code: []byte(fmt.Sprintf("(yield %s)", name)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Yield == nil {
+		return nil, fmt.Errorf("%s did not produce a value", name)
+	}
+
+	var function *janet.Function
+	err = result.Yield.Unmarshal(&function)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a function: %s", name, err)
+	}
+
+	return function, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/cfoust/cy/pkg/bind"
+	"github.com/cfoust/cy/pkg/cy/api"
 	"github.com/cfoust/cy/pkg/emu"
 	"github.com/cfoust/cy/pkg/events"
 	"github.com/cfoust/cy/pkg/janet"
@@ -40,6 +41,9 @@ type Options struct {
 	SocketPath string
 	// The name of the socket (before calculating the real path.)
 	SocketName string
+	// The state and processes of the server this one is replacing, if
+	// any. See (*Cy).Handoff.
+	Handoff *Handoff
 }
 
 type historyEvent struct {
@@ -69,6 +73,8 @@ type Cy struct {
 	timeBinds *bind.BindScope
 	// So does copy mode
 	copyBinds *bind.BindScope
+	// Read-only clients can only use the bindings in this scope
+	readOnlyBinds *bind.BindScope
 
 	clients []*Client
 
@@ -84,6 +90,14 @@ type Cy struct {
 	// (tmux does the same thing)
 	lastWrite, lastVisit map[tree.NodeID]historyEvent
 	writes, visits       chan historyEvent
+
+	// layout/tile-group, which clients call to tile their layouts again
+	// when the panes in the group they are tiling change
+	tileGroup *janet.Function
+
+	// Layouts from a resurrected snapshot that have not yet been given
+	// to a client, as Janet code
+	resurrectedLayouts []string
 }
 
 func (c *Cy) ExecuteJanet(path string) error {
@@ -194,6 +208,17 @@ func (c *Cy) pollInteractions(ctx context.Context, journal map[tree.NodeID]histo
 	}
 }
 
+func (c *Cy) Clients() (clients []api.Client) {
+	c.RLock()
+	defer c.RUnlock()
+
+	for _, client := range c.clients {
+		clients = append(clients, client)
+	}
+
+	return clients
+}
+
 func (c *Cy) getClient(id ClientID) (client *Client, found bool) {
 	c.RLock()
 	defer c.RUnlock()
@@ -227,39 +252,7 @@ func (c *Cy) Output(node tree.NodeID, index int) ([]byte, error) {
 		return nil, fmt.Errorf("node %d was not a cmd", node)
 	}
 
-	commands := r.Commands()
-
-	original := index
-
-	// Skip pending command
-	if index < 0 && len(commands) > 0 && commands[len(commands)-1].Pending {
-		index--
-	}
-
-	if index < 0 {
-		index = len(commands) + index
-	}
-
-	if index < 0 || index >= len(commands) {
-		return nil, fmt.Errorf(
-			"index %d out of range",
-			original,
-		)
-	}
-
-	command := commands[index]
-	data, ok := r.Output(command.Executed+1, command.Completed+1)
-	if !ok {
-		return nil, fmt.Errorf("no output")
-	}
-
-	// Skip the newline produced when the user originally executed the
-	// command
-	if len(data) > 1 && data[0] == '\r' && data[1] == '\n' {
-		data = data[2:]
-	}
-
-	return data, nil
+	return r.CommandOutput(index)
 }
 
 // InferClient returns the client that most recently interacted with the given
@@ -287,6 +280,17 @@ func (c *Cy) pollNodeEvents(ctx context.Context, events <-chan events.Msg) {
 		case <-ctx.Done():
 			return
 		case event := <-events:
+			if _, ok := event.(tree.ChildrenEvent); ok {
+				c.RLock()
+				clients := c.clients
+				c.RUnlock()
+
+				for _, client := range clients {
+					go client.retile()
+				}
+				continue
+			}
+
 			nodeEvent, ok := event.(tree.NodeEvent)
 			if !ok {
 				continue
@@ -300,6 +304,8 @@ func (c *Cy) pollNodeEvents(ctx context.Context, events <-chan events.Msg) {
 			switch event := nodeEvent.Event.(type) {
 			case replay.CopyEvent:
 				client.buffer = event.Text
+			case replay.ErrorEvent:
+				client.toast.Error(event.Err.Error())
 			case bind.BindEvent:
 				go client.runAction(event)
 			}
@@ -319,17 +325,18 @@ func Start(ctx context.Context, options Options) (*Cy, error) {
 	defaults := params.New()
 	t := tree.NewTree(tree.WithParams(defaults.NewChild()))
 	cy := Cy{
-		Lifetime:  util.NewLifetime(ctx),
-		tree:      t,
-		muxServer: server.New(),
-		defaults:  defaults,
-		timeBinds: timeBinds,
-		copyBinds: copyBinds,
-		options:   options,
-		lastVisit: make(map[tree.NodeID]historyEvent),
-		lastWrite: make(map[tree.NodeID]historyEvent),
-		writes:    make(chan historyEvent),
-		visits:    make(chan historyEvent),
+		Lifetime:      util.NewLifetime(ctx),
+		tree:          t,
+		muxServer:     server.New(),
+		defaults:      defaults,
+		timeBinds:     timeBinds,
+		copyBinds:     copyBinds,
+		readOnlyBinds: bind.NewBindScope(nil),
+		options:       options,
+		lastVisit:     make(map[tree.NodeID]historyEvent),
+		lastWrite:     make(map[tree.NodeID]historyEvent),
+		writes:        make(chan historyEvent),
+		visits:        make(chan historyEvent),
 	}
 	cy.toast = NewToastLogger(cy.sendToast)
 
@@ -373,9 +380,34 @@ func Start(ctx context.Context, options Options) (*Cy, error) {
 
 	cy.VM = vm
 
+	// Processes are adopted before the config is loaded so that panes can
+	// keep their IDs
+	if options.Handoff != nil {
+		err := cy.adopt(options.Handoff)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if len(options.Config) != 0 {
 		cy.loadConfig()
 	}
 
+	// Resurrection happens after the config has been loaded so that
+	// :resurrect and :data-directory can be changed there
+	if options.Handoff == nil && t.Root().Params().Resurrect() {
+		err := cy.resurrect(cy.Ctx())
+		if err != nil {
+			message := fmt.Sprintf(
+				"failed to resurrect the previous session: %s",
+				err.Error(),
+			)
+			cy.log.Error().Msg(message)
+			cy.toast.Error(message)
+		}
+	}
+
+	go cy.pollSnapshots(cy.Ctx())
+
 	return &cy, nil
 }
@@ -0,0 +1,84 @@
+package cy
+
+import (
+	"time"
+)
+
+// Suspend indicates that the client's connection dropped unexpectedly. The
+// client is kept for :reconnect-timeout seconds so that it can be resumed
+// with ResumeClient, after which it is removed. Clients that did not provide
+// a token can never be resumed, so they are removed immediately.
+func (c *Client) Suspend() {
+	timeout := c.cy.tree.Root().Params().ReconnectTimeout()
+
+	c.Lock()
+	defer c.Unlock()
+
+	// The client already exited
+	if c.Ctx().Err() != nil {
+		return
+	}
+
+	if len(c.token) == 0 || timeout <= 0 {
+		c.Cancel()
+		return
+	}
+
+	if c.expiry != nil {
+		c.expiry.Stop()
+	}
+
+	c.expiry = time.AfterFunc(
+		time.Duration(timeout)*time.Second,
+		c.Cancel,
+	)
+}
+
+// IsSuspended reports whether the client is waiting to be resumed.
+func (c *Client) IsSuspended() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.expiry != nil
+}
+
+// ResumeClient finds the suspended client that was created by `user` with
+// the token in `options`, if any, and marks it as connected again. The
+// client's environment, size, and color profile are replaced with those in
+// `options`, since it may have reconnected from a different terminal.
+func (c *Cy) ResumeClient(options ClientOptions, user User) (*Client, bool) {
+	if len(options.Token) == 0 {
+		return nil, false
+	}
+
+	c.RLock()
+	clients := c.clients
+	c.RUnlock()
+
+	for _, client := range clients {
+		client.Lock()
+		if client.token != options.Token || client.user.UID != user.UID || client.expiry == nil {
+			client.Unlock()
+			continue
+		}
+
+		// The client expired before it could be resumed
+		if !client.expiry.Stop() {
+			client.Unlock()
+			return nil, false
+		}
+
+		client.expiry = nil
+		client.env = Environment(options.Env)
+		client.profile = options.Profile
+		client.Unlock()
+
+		client.renderer.SetProfile(options.Profile)
+
+		// The client's terminal may have missed output while it was
+		// disconnected, so resizing also redraws the screen
+		client.Resize(options.Size)
+		return client, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,518 @@
+package cy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cfoust/cy/pkg/cy/cmd"
+	"github.com/cfoust/cy/pkg/janet"
+	"github.com/cfoust/cy/pkg/mux/screen/tree"
+	"github.com/cfoust/cy/pkg/mux/stream"
+	"github.com/cfoust/cy/pkg/replay"
+	"github.com/cfoust/cy/pkg/sessions"
+)
+
+const SNAPSHOT_VERSION = 1
+
+// snapshotCmd is the saved state of a pane running a command.
+type snapshotCmd struct {
+	Command   string
+	Args      []string `json:",omitempty"`
+	Directory string
+	Restart   bool
+	// The path to the .borg file the command's output was recorded to,
+	// if any.
+	Recording string `json:",omitempty"`
+
+	// The fields below are only set when the command's process is being
+	// handed to a new server (see Handoff).
+
+	// The process ID of the command.
+	PID int `json:",omitempty"`
+	// The index of the command's pseudo-terminal in Handoff.Files.
+	PTY int `json:",omitempty"`
+	// The path to a temporary .borg file containing the command's
+	// history, which is only used if the command is not being recorded.
+	Events string `json:",omitempty"`
+}
+
+// snapshotNode is the saved state of a single node in the tree.
+type snapshotNode struct {
+	ID   tree.NodeID
+	Name string
+	// The parameters set directly on this node, each formatted as Janet
+	// source.
+	Params   map[string]string `json:",omitempty"`
+	Cmd      *snapshotCmd      `json:",omitempty"`
+	Children []snapshotNode    `json:",omitempty"`
+}
+
+// snapshot is the state of a cy server that is saved to disk so that it can
+// be resurrected after the server restarts.
+type snapshot struct {
+	Version int
+	Stamp   time.Time
+	Tree    snapshotNode
+	// The layout of each client, formatted as Janet source.
+	Layouts []string `json:",omitempty"`
+}
+
+// snapshotPath gets the path to the file the server's snapshots are saved
+// to. Snapshots are disabled if there is no data directory.
+func (c *Cy) snapshotPath() (path string, ok bool) {
+	dataDir := c.tree.Root().Params().DataDirectory()
+	if len(dataDir) == 0 {
+		return "", false
+	}
+
+	name := c.options.SocketName
+	if len(name) == 0 {
+		name = "default"
+	}
+
+	return filepath.Join(dataDir, fmt.Sprintf("%s.snapshot.json", name)), true
+}
+
+// formatJanet formats a parameter value as Janet source.
+func (c *Cy) formatJanet(value interface{}) (string, error) {
+	janetValue, ok := value.(*janet.Value)
+	if !ok {
+		var err error
+		janetValue, err = c.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		defer janetValue.Free()
+	}
+
+	source := janetValue.String()
+	if len(source) == 0 {
+		return "", fmt.Errorf("value could not be formatted")
+	}
+
+	return source, nil
+}
+
+// evaluateJanet is the inverse of formatJanet.
+func (c *Cy) evaluateJanet(
+	ctx context.Context,
+	source string,
+) (*janet.Value, error) {
+	result, err := c.ExecuteCall(ctx, nil, janet.Call{
+		Code: []byte(fmt.Sprintf("(yield (quote %s))", source)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Yield == nil {
+		return nil, fmt.Errorf("source did not produce a value")
+	}
+
+	return result.Yield, nil
+}
+
+// snapshotNode saves the state of `node` and its descendants. If `handoff` is
+// not nil, the processes of any commands are also added to it.
+func (c *Cy) snapshotNode(
+	node tree.Node,
+	handoff *Handoff,
+) (saved snapshotNode, ok bool) {
+	saved = snapshotNode{
+		ID:   node.Id(),
+		Name: node.Name(),
+	}
+
+	nodeParams := node.Params()
+	for _, key := range nodeParams.Keys() {
+		value, _ := nodeParams.Get(key)
+		source, err := c.formatJanet(value)
+		if err != nil {
+			continue
+		}
+
+		if saved.Params == nil {
+			saved.Params = make(map[string]string)
+		}
+		saved.Params[key] = source
+	}
+
+	switch node := node.(type) {
+	case *tree.Group:
+		for _, child := range node.Children() {
+			savedChild, ok := c.snapshotNode(child, handoff)
+			if !ok {
+				continue
+			}
+			saved.Children = append(saved.Children, savedChild)
+		}
+		return saved, true
+	case *tree.Pane:
+		// Only panes running commands can be recreated
+		r, ok := node.Screen().(*replay.Replayable)
+		if !ok {
+			return saved, false
+		}
+
+		command, ok := r.Cmd().(*stream.Cmd)
+		if !ok {
+			return saved, false
+		}
+
+		options := command.Options()
+		saved.Cmd = &snapshotCmd{
+			Command:   options.Command,
+			Args:      options.Args,
+			Directory: options.Directory,
+			Restart:   options.Restart,
+		}
+
+		if recording, ok := cmd.Recording(r); ok {
+			saved.Cmd.Recording = recording
+		}
+
+		if handoff == nil {
+			return saved, true
+		}
+
+		// If this fails, the command is just restarted
+		err := handoff.add(c.Ctx(), r, command, saved.Cmd)
+		if err != nil {
+			c.log.Warn().Err(err).Msgf(
+				"failed to hand off pane %d",
+				node.Id(),
+			)
+		}
+
+		return saved, true
+	}
+
+	return saved, false
+}
+
+// takeSnapshot captures the current state of the server.
+func (c *Cy) takeSnapshot(ctx context.Context, handoff *Handoff) snapshot {
+	root, _ := c.snapshotNode(c.tree.Root(), handoff)
+	saved := snapshot{
+		Version: SNAPSHOT_VERSION,
+		Stamp:   time.Now(),
+		Tree:    root,
+	}
+
+	c.RLock()
+	clients := c.clients
+	c.RUnlock()
+
+	for _, client := range clients {
+		result, err := c.ExecuteCall(ctx, client, janet.Call{
+			Code: []byte(`(yield (string/format "%j" (strip-functions (layout/get))))`),
+		})
+		if err != nil || result.Yield == nil {
+			continue
+		}
+
+		var layout string
+		err = result.Yield.Unmarshal(&layout)
+		result.Yield.Free()
+		if err != nil {
+			continue
+		}
+
+		saved.Layouts = append(saved.Layouts, layout)
+	}
+
+	return saved
+}
+
+// saveSnapshot writes a snapshot of the server's state to the data
+// directory, if there is one.
+func (c *Cy) saveSnapshot(ctx context.Context) error {
+	path, ok := c.snapshotPath()
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(c.takeSnapshot(ctx, nil))
+	if err != nil {
+		return err
+	}
+
+	err = sessions.EnsureDirectory(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a crash can never leave a
+	// partially written snapshot behind
+	temp := path + ".tmp"
+	err = os.WriteFile(temp, data, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(temp, path)
+}
+
+// pollSnapshots periodically saves snapshots according to
+// :snapshot-interval.
+func (c *Cy) pollSnapshots(ctx context.Context) {
+	for {
+		interval := time.Duration(
+			c.tree.Root().Params().SnapshotInterval(),
+		) * time.Second
+
+		// Check again later in case the interval changes
+		wait := interval
+		if wait <= 0 {
+			wait = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if interval <= 0 {
+			continue
+		}
+
+		err := c.saveSnapshot(ctx)
+		if err != nil {
+			c.log.Error().Err(err).Msg("failed to save snapshot")
+		}
+	}
+}
+
+// resurrectNode recreates the children of `saved` inside of `group`,
+// recording the IDs of the new nodes in `ids`. `files` contains the
+// pseudo-terminals of any processes that were handed off.
+func (c *Cy) resurrectNode(
+	ctx context.Context,
+	group *tree.Group,
+	saved snapshotNode,
+	files []*os.File,
+	ids map[tree.NodeID]tree.NodeID,
+) {
+	for _, child := range saved.Children {
+		// Nodes keep their IDs if possible, since commands refer to
+		// their panes by ID (see $CY)
+		c.tree.ReserveID(child.ID)
+
+		var node tree.Node
+		var newGroup *tree.Group
+		if child.Cmd != nil {
+			pane, err := c.resurrectPane(
+				ctx,
+				group,
+				child.Cmd,
+				files,
+			)
+			if err != nil {
+				c.log.Error().Err(err).Msgf(
+					"failed to resurrect pane %s",
+					child.Name,
+				)
+				continue
+			}
+			node = pane
+		} else {
+			newGroup = group.NewGroup()
+			node = newGroup
+		}
+
+		ids[child.ID] = node.Id()
+		node.SetName(child.Name)
+
+		for key, source := range child.Params {
+			value, err := c.evaluateJanet(ctx, source)
+			if err != nil {
+				continue
+			}
+
+			err = node.Params().Set(key, value)
+			if err != nil {
+				value.Free()
+			}
+		}
+
+		// Parameters like :data-directory must be restored before
+		// the group's children are created
+		if newGroup != nil {
+			c.resurrectNode(ctx, newGroup, child, files, ids)
+		}
+	}
+}
+
+func (c *Cy) resurrectPane(
+	ctx context.Context,
+	group *tree.Group,
+	saved *snapshotCmd,
+	files []*os.File,
+) (*tree.Pane, error) {
+	// Commands that were recorded continue to be recorded to the same
+	// file, which contains their history. Otherwise the history of a
+	// process that was handed off is in a temporary file.
+	var history []sessions.Event
+	if len(saved.Events) > 0 {
+		defer os.Remove(saved.Events)
+
+		events, err := sessions.ReadFile(saved.Events)
+		if err != nil {
+			c.log.Warn().Err(err).Msgf(
+				"failed to read history %s",
+				saved.Events,
+			)
+		}
+		history = events
+	}
+
+	id, create := group.NewPaneCreator(ctx)
+	options := stream.CmdOptions{
+		Command:   saved.Command,
+		Args:      saved.Args,
+		Directory: saved.Directory,
+		Restart:   saved.Restart,
+		Env: map[string]string{
+			"CY": fmt.Sprintf(
+				"%s:%d",
+				c.SocketName(),
+				id,
+			),
+		},
+	}
+	dataDir := group.Params().DataDirectory()
+
+	if saved.PID != 0 && saved.PTY < len(files) {
+		replayable, err := cmd.Adopt(
+			ctx,
+			options,
+			files[saved.PTY],
+			saved.PID,
+			dataDir,
+			saved.Recording,
+			history,
+			c.timeBinds,
+			c.copyBinds,
+		)
+		if err == nil {
+			return create(replayable), nil
+		}
+
+		c.log.Warn().Err(err).Msgf(
+			"failed to adopt process %d",
+			saved.PID,
+		)
+	}
+
+	replayable, err := cmd.Resume(
+		ctx,
+		options,
+		dataDir,
+		saved.Recording,
+		history,
+		c.timeBinds,
+		c.copyBinds,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return create(replayable), nil
+}
+
+// remapLayout produces Janet code that sets a client's layout to `layout`,
+// which was saved by a previous server, after replacing the IDs of the panes
+// it refers to using `ids`. Panes that were not resurrected are detached.
+func remapLayout(layout string, ids map[tree.NodeID]tree.NodeID) string {
+	var mapping strings.Builder
+	for oldID, newID := range ids {
+		fmt.Fprintf(&mapping, "%d %d ", oldID, newID)
+	}
+
+	return fmt.Sprintf(`
+(def ids {%s})
+(layout/set
+  (layout/map
+    (fn [node]
+      (if (not (layout/pane? node)) (break node))
+      (def new-node (table ;(kvs node)))
+      (put new-node :id (get ids (node :id)))
+      (table/to-struct new-node))
+    (quote %s)))
+`, mapping.String(), layout)
+}
+
+// restoreSnapshot recreates the groups and panes in `saved`. The layouts in
+// the snapshot are given to the first clients that connect.
+func (c *Cy) restoreSnapshot(
+	ctx context.Context,
+	saved snapshot,
+	files []*os.File,
+) error {
+	if saved.Version != SNAPSHOT_VERSION {
+		return fmt.Errorf(
+			"unsupported snapshot version %d",
+			saved.Version,
+		)
+	}
+
+	ids := make(map[tree.NodeID]tree.NodeID)
+	c.resurrectNode(ctx, c.tree.Root(), saved.Tree, files, ids)
+
+	c.Lock()
+	for _, layout := range saved.Layouts {
+		c.resurrectedLayouts = append(
+			c.resurrectedLayouts,
+			remapLayout(layout, ids),
+		)
+	}
+	c.Unlock()
+
+	return nil
+}
+
+// resurrect recreates the groups and panes in the most recent snapshot saved
+// by a previous server.
+func (c *Cy) resurrect(ctx context.Context) error {
+	path, ok := c.snapshotPath()
+	if !ok {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var saved snapshot
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		return err
+	}
+
+	return c.restoreSnapshot(ctx, saved, nil)
+}
+
+// popResurrectedLayout removes and returns the next layout from the
+// snapshot that has not yet been given to a client.
+func (c *Cy) popResurrectedLayout() (code string, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if len(c.resurrectedLayouts) == 0 {
+		return "", false
+	}
+
+	code = c.resurrectedLayouts[0]
+	c.resurrectedLayouts = c.resurrectedLayouts[1:]
+	return code, true
+}
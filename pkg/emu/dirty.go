@@ -95,3 +95,11 @@ func (d *Dirty) Reset() {
 func (d *Dirty) ScreenChanged() bool {
 	return d.Flag&ChangedScreen != 0
 }
+
+// Bell returns true if the bell has rung since the last call to Bell or
+// Reset().
+func (d *Dirty) Bell() bool {
+	rang := d.Flag&ChangedBell != 0
+	d.Flag &^= ChangedBell
+	return rang
+}
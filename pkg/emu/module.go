@@ -68,6 +68,7 @@ type WriteID uint32
 const (
 	ChangedScreen ChangeFlag = 1 << iota
 	ChangedTitle
+	ChangedBell
 )
 
 type Glyph struct {
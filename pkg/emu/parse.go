@@ -55,8 +55,7 @@ func (t *State) Execute(b byte) {
 		t.newline(t.mode&ModeCRLF != 0)
 	// BEL
 	case '\a':
-		// TODO: emit sound
-		// TODO: window alert if not focused
+		t.dirty.Flag |= ChangedBell
 	}
 }
 
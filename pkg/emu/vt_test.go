@@ -109,6 +109,21 @@ func TestPrompt(t *testing.T) {
 	require.True(t, ok)
 }
 
+func TestBell(t *testing.T) {
+	term := New()
+	dirty := term.Changes()
+
+	_, err := term.Write([]byte("foo\a"))
+	require.NoError(t, err)
+	require.True(t, dirty.Bell())
+	require.False(t, dirty.Bell())
+
+	// BEL can also terminate an OSC sequence, which is not a bell
+	_, err = term.Write([]byte("\033]0;title\a"))
+	require.NoError(t, err)
+	require.False(t, dirty.Bell())
+}
+
 func TestTabsBug(t *testing.T) {
 	term := New()
 	// This is the simplest example of a bug that I encountered with tabs.
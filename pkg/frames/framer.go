@@ -52,6 +52,13 @@ func (f *Framer) Resize(size geom.Size) error {
 	return nil
 }
 
+// Frame returns the Frame currently in use.
+func (f *Framer) Frame() Frame {
+	f.RLock()
+	defer f.RUnlock()
+	return f.frame
+}
+
 func (f *Framer) Set(frame Frame) {
 	f.Lock()
 	f.frame = frame
@@ -31,6 +31,14 @@ func setColor(info *terminfo.Terminfo, color emu.Color, isBg bool) []byte {
 		} else {
 			fmt.Fprintf(data, "\x1b[38;2;%d;%d;%dm", r, g, b)
 		}
+	} else if ansi, ok := color.ANSI(); ok && ansi >= 8 {
+		// The terminfo entries for some 16-color terminals, such as
+		// the Linux console, cannot express the bright colors
+		if isBg {
+			fmt.Fprintf(data, "\x1b[%dm", 100+ansi-8)
+		} else {
+			fmt.Fprintf(data, "\x1b[%dm", 90+ansi-8)
+		}
 	} else if xterm, ok := color.XTerm(); ok {
 		code := terminfo.SetABackground
 		if !isBg {
@@ -16,7 +16,17 @@ func testBytes(
 	name string,
 	bytes []byte,
 ) {
-	info, _ := terminfo.Load("xterm-256color")
+	testTerminfo(t, "xterm-256color", name, bytes)
+}
+
+func testTerminfo(
+	t *testing.T,
+	term string,
+	name string,
+	bytes []byte,
+) {
+	info, err := terminfo.Load(term)
+	require.NoError(t, err)
 	termA := emu.New()
 	termA.Write(bytes)
 
@@ -60,3 +70,10 @@ func TestAttributes(t *testing.T) {
 
 	testBytes(t, "style", []byte("\033[48;2;255;0;0m           \033[0m\033[3;38;2;0;0;255;48;2;255;0;0mtest\033[0m"))
 }
+
+func TestBrightColors(t *testing.T) {
+	for _, term := range []string{"xterm-256color", "linux"} {
+		testTerminfo(t, term, "bright fg", []byte("\033[91mon\033[0m off"))
+		testTerminfo(t, term, "bright bg", []byte("\033[101mon\033[0m off"))
+	}
+}
@@ -0,0 +1,197 @@
+package hint
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cfoust/cy/pkg/emu"
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/geom/tty"
+	"github.com/cfoust/cy/pkg/taro"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createImage creates a rectangular image containing `lines`.
+func createImage(lines ...string) (img image.Image) {
+	var width int
+	for _, line := range lines {
+		width = geom.Max(width, len(line))
+	}
+
+	for _, line := range lines {
+		img = append(img, emu.LineFromString(
+			line+strings.Repeat(" ", width-len(line)),
+		))
+	}
+	return
+}
+
+var (
+	hashPattern = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+	pathPattern = regexp.MustCompile(`(/[\w.-]+)+`)
+)
+
+func TestFind(t *testing.T) {
+	img := createImage(
+		"commit 1a2b3c4d /usr/bin",
+		"nothing here",
+		"/tmp/foo and 1a2b3c4d",
+	)
+
+	matches := Find(img, hashPattern, pathPattern)
+	require.Equal(t, 4, len(matches))
+	require.Equal(t, "1a2b3c4d", matches[0].Text)
+	require.Equal(t, 0, matches[0].R)
+	require.Equal(t, 7, matches[0].C0)
+	require.Equal(t, "/usr/bin", matches[1].Text)
+	require.Equal(t, "/tmp/foo", matches[2].Text)
+	require.Equal(t, 2, matches[2].R)
+}
+
+func TestOverlap(t *testing.T) {
+	img := createImage("/tmp/1a2b3c4d")
+	matches := Find(img, hashPattern, pathPattern)
+	require.Equal(t, 1, len(matches))
+	require.Equal(t, "/tmp/1a2b3c4d", matches[0].Text)
+}
+
+func TestLabels(t *testing.T) {
+	require.Equal(t, []string{"a", "b"}, createLabels("ab", 2))
+	require.Equal(
+		t,
+		[]string{"aa", "ab", "ba"},
+		createLabels("ab", 3),
+	)
+	require.Equal(t, 0, len(createLabels("ab", 0)))
+
+	// Repeated characters are ignored
+	require.Equal(
+		t,
+		[]string{"aa", "ab", "ba"},
+		createLabels("aab", 3),
+	)
+}
+
+func TestCheckAlphabet(t *testing.T) {
+	img := createImage("/tmp/foo /tmp/bar /tmp/foo")
+	matches := Find(img, pathPattern)
+
+	require.NoError(t, CheckAlphabet("ab", matches))
+	require.NoError(t, CheckAlphabet("a", matches[:1]))
+	require.NoError(t, CheckAlphabet("a", []Match{matches[0], matches[2]}))
+	require.Error(t, CheckAlphabet("a", matches))
+	require.Error(t, CheckAlphabet("aaa", matches))
+}
+
+func TestChoose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	img := createImage(
+		"/tmp/foo /tmp/bar",
+		"/tmp/foo",
+	)
+
+	result := make(chan interface{}, 1)
+	h := newHint(
+		ctx,
+		img,
+		WithResult(result),
+		WithAlphabet("xy"),
+		WithPatterns(pathPattern),
+	)
+
+	// Identical matches share a label
+	require.Equal(t, "x", h.matches[0].Label)
+	require.Equal(t, "y", h.matches[1].Label)
+	require.Equal(t, "x", h.matches[2].Label)
+
+	test := taro.Test(h)
+
+	// Keys that aren't labels are ignored
+	test("z")
+	require.Equal(t, "", h.typed)
+
+	test("y")
+	require.Equal(t, "/tmp/bar", <-result)
+}
+
+func TestViewMultibyte(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	img := createImage("/a /b /c")
+	h := newHint(
+		ctx,
+		img,
+		WithAlphabet("éü"),
+		WithPatterns(pathPattern),
+	)
+
+	match := h.matches[0]
+	label := []rune(match.Label)
+	require.Len(t, label, 2)
+
+	test := taro.Test(h)
+	test(string(label[0]))
+	require.Equal(t, string(label[0]), h.typed)
+
+	state := tty.New(img.Size())
+	h.View(state)
+
+	// Each character in the label occupies one cell and only the typed
+	// ones are highlighted
+	line := state.Image[match.R]
+	require.Equal(t, label[0], line[match.C0].Char)
+	require.Equal(t, label[1], line[match.C0+1].Char)
+	require.NotEqual(t, line[match.C0].BG, line[match.C0+1].BG)
+}
+
+func TestBackspaceMultibyte(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	img := createImage("/a /b /c")
+	h := newHint(
+		ctx,
+		img,
+		WithAlphabet("éü"),
+		WithPatterns(pathPattern),
+	)
+
+	label := []rune(h.matches[0].Label)
+	test := taro.Test(h)
+	test(string(label[0]))
+	require.Equal(t, string(label[0]), h.typed)
+
+	test("backspace")
+	require.Equal(t, "", h.typed)
+
+	// Backspacing with nothing typed does nothing
+	test("backspace")
+	require.Equal(t, "", h.typed)
+
+	test(string(label[0]))
+	require.Equal(t, string(label[0]), h.typed)
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	result := make(chan interface{}, 1)
+	h := newHint(
+		ctx,
+		createImage("/tmp/foo"),
+		WithResult(result),
+		WithPatterns(pathPattern),
+	)
+
+	test := taro.Test(h)
+	test("esc")
+	require.Nil(t, <-result)
+}
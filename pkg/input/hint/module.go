@@ -0,0 +1,265 @@
+package hint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/cfoust/cy/pkg/emu"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/replay/motion"
+	"github.com/cfoust/cy/pkg/taro"
+	"github.com/cfoust/cy/pkg/util"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DEFAULT_ALPHABET contains the characters used for hint labels, ordered so
+// that the easiest keys to reach are used first.
+const DEFAULT_ALPHABET = "asdfghjklqwertyuiopzxcvbnm"
+
+// A Match is a region of the screen that matched one of the patterns.
+type Match struct {
+	// The row of the match and the columns it spans, [C0, C1).
+	emu.ScreenLine
+	Text string
+	// The key sequence the user must type to choose this match.
+	Label string
+}
+
+// Hint labels every match of a set of regular expressions on the screen with
+// a short key sequence, then returns the text of the match the user chooses.
+type Hint struct {
+	util.Lifetime
+
+	// The state of the screen before Hint was started.
+	initial image.Image
+
+	result chan<- interface{}
+	render *taro.Renderer
+
+	patterns []*regexp.Regexp
+	alphabet string
+
+	labelColor, typedColor, matchColor lipgloss.Color
+
+	matches []Match
+	// The label characters the user has typed so far.
+	typed string
+}
+
+var _ taro.Model = (*Hint)(nil)
+
+func (h *Hint) quit() (taro.Model, tea.Cmd) {
+	return h, tea.Batch(
+		func() tea.Msg {
+			h.Cancel()
+			return nil
+		},
+		tea.Quit,
+	)
+}
+
+func (h *Hint) Init() taro.Cmd {
+	return nil
+}
+
+// findMatches returns all of the non-overlapping matches of `patterns` in
+// `img`, ordered from the top left of the screen to the bottom right. When two
+// matches overlap, the one that starts first (or is longer) wins.
+func findMatches(img image.Image, patterns []*regexp.Regexp) (matches []Match) {
+	for row, line := range img {
+		var found []emu.ScreenLine
+		for _, re := range patterns {
+			for _, loc := range motion.FindAllLine(re, line) {
+				if loc[0] == loc[1] {
+					continue
+				}
+
+				found = append(found, emu.ScreenLine{
+					R:  row,
+					C0: loc[0],
+					C1: loc[1],
+				})
+			}
+		}
+
+		sort.SliceStable(found, func(i, j int) bool {
+			if found[i].C0 != found[j].C0 {
+				return found[i].C0 < found[j].C0
+			}
+			return found[i].C1 > found[j].C1
+		})
+
+		end := 0
+		for _, match := range found {
+			if match.C0 < end {
+				continue
+			}
+
+			end = match.C1
+			match.Chars = line[match.C0:match.C1]
+			matches = append(matches, Match{
+				ScreenLine: match,
+				Text:       match.Chars.String(),
+			})
+		}
+	}
+
+	return matches
+}
+
+// uniqueRunes returns the distinct characters in `alphabet` in the order
+// they first appear.
+func uniqueRunes(alphabet string) (chars []rune) {
+	for _, char := range alphabet {
+		if !slices.Contains(chars, char) {
+			chars = append(chars, char)
+		}
+	}
+	return
+}
+
+// numLabels returns the number of distinct labels needed for `matches`.
+func numLabels(matches []Match) int {
+	texts := make(map[string]struct{})
+	for _, match := range matches {
+		texts[match.Text] = struct{}{}
+	}
+	return len(texts)
+}
+
+// CheckAlphabet returns an error if `alphabet` cannot produce a distinct
+// label for each of `matches`.
+func CheckAlphabet(alphabet string, matches []Match) error {
+	chars := uniqueRunes(alphabet)
+	n := numLabels(matches)
+	if len(chars) >= 2 || n <= len(chars) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"alphabet %q must contain at least two distinct characters to label %d matches",
+		alphabet,
+		n,
+	)
+}
+
+// createLabels generates `n` labels from `alphabet`, ignoring repeated
+// characters. All labels have the same length so that no label is a prefix
+// of another.
+func createLabels(alphabet string, n int) (labels []string) {
+	chars := uniqueRunes(alphabet)
+	if n == 0 || len(chars) == 0 {
+		return
+	}
+
+	length, capacity := 1, len(chars)
+	for capacity < n && len(chars) > 1 {
+		length++
+		capacity *= len(chars)
+	}
+
+	for i := 0; i < n; i++ {
+		label := make([]rune, length)
+		value := i
+		for j := length - 1; j >= 0; j-- {
+			label[j] = chars[value%len(chars)]
+			value /= len(chars)
+		}
+		labels = append(labels, string(label))
+	}
+
+	return
+}
+
+// assignLabels gives each match a label. Matches with identical text share
+// the same label.
+func assignLabels(matches []Match, alphabet string) {
+	indices := make(map[string]int)
+	for _, match := range matches {
+		if _, ok := indices[match.Text]; ok {
+			continue
+		}
+		indices[match.Text] = len(indices)
+	}
+
+	labels := createLabels(alphabet, len(indices))
+	for i := range matches {
+		matches[i].Label = labels[indices[matches[i].Text]]
+	}
+}
+
+type Setting func(context.Context, *Hint)
+
+func WithResult(result chan<- interface{}) Setting {
+	return func(ctx context.Context, h *Hint) {
+		h.result = result
+	}
+}
+
+// WithAlphabet sets the characters used to create hint labels.
+func WithAlphabet(alphabet string) Setting {
+	return func(ctx context.Context, h *Hint) {
+		h.alphabet = alphabet
+	}
+}
+
+// WithColors sets the background colors of labels, of the characters of
+// labels that have already been typed, and of the text of matches.
+func WithColors(label, typed, match lipgloss.Color) Setting {
+	return func(ctx context.Context, h *Hint) {
+		h.labelColor = label
+		h.typedColor = typed
+		h.matchColor = match
+	}
+}
+
+// WithPatterns sets the regular expressions used to find hints.
+func WithPatterns(patterns ...*regexp.Regexp) Setting {
+	return func(ctx context.Context, h *Hint) {
+		h.patterns = patterns
+	}
+}
+
+func newHint(
+	ctx context.Context,
+	initial image.Image,
+	settings ...Setting,
+) *Hint {
+	h := &Hint{
+		Lifetime:   util.NewLifetime(ctx),
+		render:     taro.NewRenderer(),
+		initial:    initial,
+		alphabet:   DEFAULT_ALPHABET,
+		labelColor: lipgloss.Color("#F25F5C"),
+		typedColor: lipgloss.Color("#7768AE"),
+		matchColor: lipgloss.Color("3"),
+	}
+
+	for _, setting := range settings {
+		setting(h.Ctx(), h)
+	}
+
+	h.matches = findMatches(initial, h.patterns)
+	assignLabels(h.matches, h.alphabet)
+	return h
+}
+
+// Find returns the matches of `patterns` on `initial` without starting an
+// interactive session.
+func Find(initial image.Image, patterns ...*regexp.Regexp) []Match {
+	return findMatches(initial, patterns)
+}
+
+func New(
+	ctx context.Context,
+	initial image.Image,
+	settings ...Setting,
+) *taro.Program {
+	h := newHint(ctx, initial, settings...)
+	return taro.New(h.Ctx(), h)
+}
@@ -0,0 +1,61 @@
+package hint
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cfoust/cy/pkg/taro"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (h *Hint) choose(value interface{}) (taro.Model, tea.Cmd) {
+	if h.result != nil {
+		h.result <- value
+	}
+	return h.quit()
+}
+
+// handleLabel processes a label character typed by the user, choosing a match
+// if the label is complete.
+func (h *Hint) handleLabel(char string) (taro.Model, tea.Cmd) {
+	typed := h.typed + char
+
+	var isPrefix bool
+	for _, match := range h.matches {
+		if match.Label == typed {
+			return h.choose(match.Text)
+		}
+
+		if strings.HasPrefix(match.Label, typed) {
+			isPrefix = true
+		}
+	}
+
+	// Ignore keys that do not lead to any label
+	if isPrefix {
+		h.typed = typed
+	}
+
+	return h, nil
+}
+
+func (h *Hint) Update(msg tea.Msg) (taro.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case taro.KeyMsg:
+		switch msg.Type {
+		case taro.KeyEsc, taro.KeyCtrlC:
+			return h.choose(nil)
+		case taro.KeyBackspace:
+			// Labels may contain multi-byte characters, so remove the
+			// last rune rather than the last byte
+			_, size := utf8.DecodeLastRuneInString(h.typed)
+			h.typed = h.typed[:len(h.typed)-size]
+			return h, nil
+		case taro.KeyRunes:
+			return h.handleLabel(string(msg.Runes))
+		}
+	}
+
+	return h, nil
+}
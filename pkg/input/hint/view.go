@@ -0,0 +1,60 @@
+package hint
+
+import (
+	"strings"
+
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/geom/tty"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (h *Hint) View(state *tty.State) {
+	image.Copy(geom.Vec2{}, state.Image, h.initial)
+	state.CursorVisible = false
+
+	size := state.Image.Size()
+
+	dimFG := h.render.ConvertLipgloss(lipgloss.Color("8"))
+	matchFG := h.render.ConvertLipgloss(lipgloss.Color("0"))
+	matchBG := h.render.ConvertLipgloss(h.matchColor)
+	labelFG := h.render.ConvertLipgloss(lipgloss.Color("15"))
+	labelBG := h.render.ConvertLipgloss(h.labelColor)
+	typedBG := h.render.ConvertLipgloss(h.typedColor)
+
+	// Dim everything that isn't a match so hints stand out
+	for _, line := range state.Image {
+		for col := range line {
+			line[col].FG = dimFG
+		}
+	}
+
+	numTyped := len([]rune(h.typed))
+	for _, match := range h.matches {
+		if match.R >= size.R || !strings.HasPrefix(match.Label, h.typed) {
+			continue
+		}
+
+		line := state.Image[match.R]
+		for col := match.C0; col < match.C1 && col < len(line); col++ {
+			line[col].FG = matchFG
+			line[col].BG = matchBG
+		}
+
+		// Labels are drawn over the beginning of the match
+		for i, char := range []rune(match.Label) {
+			col := match.C0 + i
+			if col >= len(line) {
+				break
+			}
+
+			line[col].Char = char
+			line[col].FG = labelFG
+			line[col].BG = labelBG
+			if i < numTyped {
+				line[col].BG = typedBG
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package panes
+
+import "unicode"
+
+const (
+	GLYPH_WIDTH  = 3
+	GLYPH_HEIGHT = 5
+)
+
+// glyphs are large versions of the characters used for labels. Each `#` is
+// drawn as a filled cell.
+var glyphs = map[rune][GLYPH_HEIGHT]string{
+	'0': {"###", "# #", "# #", "# #", "###"},
+	'1': {" # ", "## ", " # ", " # ", "###"},
+	'2': {"###", "  #", "###", "#  ", "###"},
+	'3': {"###", "  #", "###", "  #", "###"},
+	'4': {"# #", "# #", "###", "  #", "  #"},
+	'5': {"###", "#  ", "###", "  #", "###"},
+	'6': {"###", "#  ", "###", "# #", "###"},
+	'7': {"###", "  #", "  #", "  #", "  #"},
+	'8': {"###", "# #", "###", "# #", "###"},
+	'9': {"###", "# #", "###", "  #", "###"},
+	'a': {"###", "# #", "###", "# #", "# #"},
+	'b': {"## ", "# #", "## ", "# #", "## "},
+	'c': {"###", "#  ", "#  ", "#  ", "###"},
+	'd': {"## ", "# #", "# #", "# #", "## "},
+	'e': {"###", "#  ", "###", "#  ", "###"},
+	'f': {"###", "#  ", "###", "#  ", "#  "},
+	'g': {"###", "#  ", "# #", "# #", "###"},
+	'h': {"# #", "# #", "###", "# #", "# #"},
+	'i': {"###", " # ", " # ", " # ", "###"},
+	'j': {"  #", "  #", "  #", "# #", "###"},
+	'k': {"# #", "# #", "## ", "# #", "# #"},
+	'l': {"#  ", "#  ", "#  ", "#  ", "###"},
+	'm': {"# #", "###", "###", "# #", "# #"},
+	'n': {"###", "# #", "# #", "# #", "# #"},
+	'o': {"###", "# #", "# #", "# #", "###"},
+	'p': {"###", "# #", "###", "#  ", "#  "},
+	'q': {"###", "# #", "# #", "###", "  #"},
+	'r': {"###", "# #", "## ", "# #", "# #"},
+	's': {"###", "#  ", "###", "  #", "###"},
+	't': {"###", " # ", " # ", " # ", " # "},
+	'u': {"# #", "# #", "# #", "# #", "###"},
+	'v': {"# #", "# #", "# #", "# #", " # "},
+	'w': {"# #", "# #", "###", "###", "# #"},
+	'x': {"# #", "# #", " # ", "# #", "# #"},
+	'y': {"# #", "# #", "###", " # ", " # "},
+	'z': {"###", "  #", " # ", "#  ", "###"},
+}
+
+// getGlyph returns the large version of char, if there is one.
+func getGlyph(char rune) (glyph [GLYPH_HEIGHT]string, ok bool) {
+	glyph, ok = glyphs[unicode.ToLower(char)]
+	return
+}
@@ -0,0 +1,134 @@
+package panes
+
+import (
+	"context"
+
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/taro"
+	"github.com/cfoust/cy/pkg/util"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DEFAULT_LABELS contains the characters used to label panes, in the order
+// they are assigned.
+const DEFAULT_LABELS = "1234567890abcdefghijklmnopqrstuvwxyz"
+
+// A Target is a region of the screen the user can choose.
+type Target struct {
+	geom.Rect
+	// The value sent on the result channel if this Target is chosen.
+	Value interface{}
+	// Whether this Target is drawn with the active color.
+	Active bool
+	// The key that chooses this Target. This is set by Panes.
+	Label rune
+}
+
+// Panes draws a large label over each of a set of regions of the screen,
+// then returns the value of the region whose label the user types.
+type Panes struct {
+	util.Lifetime
+
+	// The state of the screen before Panes was started.
+	initial image.Image
+
+	result chan<- interface{}
+	render *taro.Renderer
+
+	labels      string
+	color       lipgloss.Color
+	activeColor lipgloss.Color
+
+	targets []Target
+}
+
+var _ taro.Model = (*Panes)(nil)
+
+func (p *Panes) quit() (taro.Model, tea.Cmd) {
+	return p, tea.Batch(
+		func() tea.Msg {
+			p.Cancel()
+			return nil
+		},
+		tea.Quit,
+	)
+}
+
+func (p *Panes) Init() taro.Cmd {
+	return nil
+}
+
+// assignLabels gives each Target one character of `labels`. Targets beyond
+// the length of `labels` cannot be chosen and are removed.
+func assignLabels(targets []Target, labels string) (labeled []Target) {
+	chars := []rune(labels)
+	for i, target := range targets {
+		if i >= len(chars) {
+			break
+		}
+
+		target.Label = chars[i]
+		labeled = append(labeled, target)
+	}
+	return
+}
+
+type Setting func(context.Context, *Panes)
+
+func WithResult(result chan<- interface{}) Setting {
+	return func(ctx context.Context, p *Panes) {
+		p.result = result
+	}
+}
+
+// WithLabels sets the characters used to label Targets.
+func WithLabels(labels string) Setting {
+	return func(ctx context.Context, p *Panes) {
+		p.labels = labels
+	}
+}
+
+// WithColors sets the colors of the labels drawn over inactive and active
+// Targets.
+func WithColors(color, activeColor lipgloss.Color) Setting {
+	return func(ctx context.Context, p *Panes) {
+		p.color = color
+		p.activeColor = activeColor
+	}
+}
+
+func newPanes(
+	ctx context.Context,
+	initial image.Image,
+	targets []Target,
+	settings ...Setting,
+) *Panes {
+	p := &Panes{
+		Lifetime:    util.NewLifetime(ctx),
+		render:      taro.NewRenderer(),
+		initial:     initial,
+		labels:      DEFAULT_LABELS,
+		color:       lipgloss.Color("#7768AE"),
+		activeColor: lipgloss.Color("#F25F5C"),
+	}
+
+	for _, setting := range settings {
+		setting(p.Ctx(), p)
+	}
+
+	p.targets = assignLabels(targets, p.labels)
+	return p
+}
+
+func New(
+	ctx context.Context,
+	initial image.Image,
+	targets []Target,
+	settings ...Setting,
+) *taro.Program {
+	p := newPanes(ctx, initial, targets, settings...)
+	return taro.New(p.Ctx(), p)
+}
@@ -0,0 +1,95 @@
+package panes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/geom/tty"
+	"github.com/cfoust/cy/pkg/taro"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	targets := assignLabels(
+		[]Target{{Value: 0}, {Value: 1}, {Value: 2}},
+		"xy",
+	)
+	require.Equal(t, 2, len(targets))
+	require.Equal(t, 'x', targets[0].Label)
+	require.Equal(t, 'y', targets[1].Label)
+}
+
+func TestChoose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	result := make(chan interface{}, 1)
+	p := newPanes(
+		ctx,
+		image.New(geom.Vec2{R: 10, C: 20}),
+		[]Target{
+			{Value: 0, Rect: geom.Rect{Size: geom.Vec2{R: 10, C: 10}}},
+			{Value: 1, Rect: geom.Rect{
+				Position: geom.Vec2{C: 10},
+				Size:     geom.Vec2{R: 10, C: 10},
+			}},
+		},
+		WithResult(result),
+	)
+
+	test := taro.Test(p)
+
+	// Keys that aren't labels are ignored
+	test("z")
+	require.Equal(t, 0, len(result))
+
+	test("2")
+	require.Equal(t, 1, <-result)
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	result := make(chan interface{}, 1)
+	p := newPanes(
+		ctx,
+		image.New(geom.Vec2{R: 10, C: 20}),
+		[]Target{{Value: 0}},
+		WithResult(result),
+	)
+
+	test := taro.Test(p)
+	test("esc")
+	require.Nil(t, <-result)
+}
+
+func TestView(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	size := geom.Vec2{R: 10, C: 20}
+	p := newPanes(
+		ctx,
+		image.New(size),
+		[]Target{
+			{Rect: geom.Rect{Size: geom.Vec2{R: 10, C: 10}}},
+			// Too small for a large label
+			{Rect: geom.Rect{
+				Position: geom.Vec2{C: 10},
+				Size:     geom.Vec2{R: 2, C: 2},
+			}},
+		},
+	)
+
+	state := tty.New(size)
+	p.View(state)
+
+	// The top of the large "1" is filled in, but the cell beside it is not
+	require.NotEqual(t, state.Image[2][3].BG, state.Image[2][4].BG)
+	require.Equal(t, state.Image[0][0].BG, state.Image[2][3].BG)
+	require.Equal(t, '2', state.Image[1][11].Char)
+}
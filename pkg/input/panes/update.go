@@ -0,0 +1,39 @@
+package panes
+
+import (
+	"github.com/cfoust/cy/pkg/taro"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (p *Panes) choose(value interface{}) (taro.Model, tea.Cmd) {
+	if p.result != nil {
+		p.result <- value
+	}
+	return p.quit()
+}
+
+func (p *Panes) Update(msg tea.Msg) (taro.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case taro.KeyMsg:
+		switch msg.Type {
+		case taro.KeyEsc, taro.KeyCtrlC:
+			return p.choose(nil)
+		case taro.KeyRunes:
+			if len(msg.Runes) != 1 {
+				return p, nil
+			}
+
+			for _, target := range p.targets {
+				if target.Label == msg.Runes[0] {
+					return p.choose(target.Value)
+				}
+			}
+
+			// Ignore keys that are not labels
+			return p, nil
+		}
+	}
+
+	return p, nil
+}
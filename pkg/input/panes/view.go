@@ -0,0 +1,82 @@
+package panes
+
+import (
+	"github.com/cfoust/cy/pkg/emu"
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/geom/tty"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// drawLabel draws `target`'s label in the center of its region, using a
+// large glyph if there is enough room for one.
+func drawLabel(img image.Image, target Target, fg, bg emu.Color) {
+	size := img.Size()
+	setCell := func(row, col int, char rune, fg, bg emu.Color) {
+		if row < 0 || row >= size.R || col < 0 || col >= size.C {
+			return
+		}
+
+		img[row][col].Char = char
+		img[row][col].FG = fg
+		img[row][col].BG = bg
+	}
+
+	rect := target.Rect
+	if rect.Size.R <= 0 || rect.Size.C <= 0 {
+		return
+	}
+
+	glyph, ok := getGlyph(target.Label)
+	if !ok || rect.Size.R < GLYPH_HEIGHT || rect.Size.C < GLYPH_WIDTH {
+		setCell(
+			rect.Position.R+rect.Size.R/2,
+			rect.Position.C+rect.Size.C/2,
+			target.Label,
+			fg,
+			bg,
+		)
+		return
+	}
+
+	origin := geom.Vec2{
+		R: rect.Position.R + (rect.Size.R-GLYPH_HEIGHT)/2,
+		C: rect.Position.C + (rect.Size.C-GLYPH_WIDTH)/2,
+	}
+	for row, line := range glyph {
+		for col, char := range line {
+			if char != '#' {
+				continue
+			}
+
+			setCell(origin.R+row, origin.C+col, ' ', fg, bg)
+		}
+	}
+}
+
+func (p *Panes) View(state *tty.State) {
+	image.Copy(geom.Vec2{}, state.Image, p.initial)
+	state.CursorVisible = false
+
+	dimFG := p.render.ConvertLipgloss(lipgloss.Color("8"))
+	labelFG := p.render.ConvertLipgloss(lipgloss.Color("15"))
+	labelBG := p.render.ConvertLipgloss(p.color)
+	activeBG := p.render.ConvertLipgloss(p.activeColor)
+
+	// Dim the screen so the labels stand out
+	for _, line := range state.Image {
+		for col := range line {
+			line[col].FG = dimFG
+		}
+	}
+
+	for _, target := range p.targets {
+		bg := labelBG
+		if target.Active {
+			bg = activeBG
+		}
+
+		drawLabel(state.Image, target, labelFG, bg)
+	}
+}
@@ -17,6 +17,8 @@ const (
 	MessageTypeRPCRequest
 	MessageTypeRPCResponse
 	MessageTypeClose
+	MessageTypeReconnect
+	MessageTypeHandshakeResponse
 )
 
 type Message interface {
@@ -44,6 +46,12 @@ type CloseMessage struct {
 
 func (i CloseMessage) Type() MessageType { return MessageTypeClose }
 
+// Tells the client that the server is being replaced (such as during an
+// upgrade) and that it should connect to the same socket again.
+type ReconnectMessage struct{}
+
+func (i ReconnectMessage) Type() MessageType { return MessageTypeReconnect }
+
 // Used when the client terminal is resized.
 type SizeMessage struct {
 	Rows    int
@@ -62,10 +70,41 @@ type HandshakeMessage struct {
 	Shell   string
 	Size    geom.Vec2
 	Profile termenv.Profile
+	// Whether the client may only watch panes and not send them input.
+	ReadOnly bool
+	// The ID of the client whose layout this client should mirror, or 0
+	// if it should not follow another client.
+	Follow int32
+	// A secret that identifies this client across connections. If the
+	// connection drops, a client that reconnects with the same token
+	// resumes the client it had before.
+	Token string
+	// The version of the protocol the client speaks. Clients that
+	// predate protocol versioning send 0.
+	Version int
+	// The oldest version of the protocol the server may speak for the
+	// client to work correctly.
+	MinVersion int
+	// The features the client detected in its terminal.
+	Capabilities []Capability
 }
 
 func (i HandshakeMessage) Type() MessageType { return MessageTypeHandshake }
 
+// Sent by the server in response to a HandshakeMessage from a client that
+// speaks version 1 or later of the protocol.
+type HandshakeResponseMessage struct {
+	// The version of the protocol the server speaks.
+	Version int
+	// The capabilities of the client's terminal that the server will use,
+	// which are those that both the client and server support.
+	Capabilities []Capability
+}
+
+func (i HandshakeResponseMessage) Type() MessageType {
+	return MessageTypeHandshakeResponse
+}
+
 // Send an error to the client. Used before closing the connection.
 type ErrorMessage struct {
 	Message string
@@ -33,6 +33,10 @@ func Decode(data []byte) (Message, error) {
 		msg = &SizeMessage{}
 	case MessageTypeClose:
 		msg = &CloseMessage{}
+	case MessageTypeReconnect:
+		msg = &ReconnectMessage{}
+	case MessageTypeHandshakeResponse:
+		msg = &HandshakeResponseMessage{}
 	case MessageTypeRPCRequest:
 		msg = &RPCRequestMessage{}
 	case MessageTypeRPCResponse:
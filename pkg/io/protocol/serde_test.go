@@ -18,3 +18,34 @@ func TestSerialization(t *testing.T) {
 	after, err := Decode(encoded)
 	assert.Equal(t, &before, after, "should yield same result")
 }
+
+func TestHandshake(t *testing.T) {
+	before := HandshakeMessage{
+		Version:    Version,
+		MinVersion: MinVersion,
+		Capabilities: []Capability{
+			CapabilityTrueColor,
+			"unknown",
+		},
+	}
+
+	encoded, err := Encode(before)
+	assert.NoError(t, err)
+
+	after, err := Decode(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, &before, after)
+
+	assert.Equal(
+		t,
+		[]Capability{CapabilityTrueColor},
+		Negotiate(before.Capabilities),
+	)
+}
+
+func TestCheckClientVersion(t *testing.T) {
+	assert.NoError(t, CheckClientVersion(0, 0))
+	assert.NoError(t, CheckClientVersion(Version, MinVersion))
+	assert.NoError(t, CheckClientVersion(Version+1, MinVersion))
+	assert.Error(t, CheckClientVersion(Version+1, Version+1))
+}
@@ -0,0 +1,78 @@
+package protocol
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Version is the version of the protocol spoken by this build of cy. It
+// must be incremented whenever messages change in a way that peers should
+// know about.
+const Version = 1
+
+// MinVersion is the oldest version of the protocol a peer can speak and
+// still work with this build of cy. Increment it when a change breaks
+// older peers.
+const MinVersion = 0
+
+// A Capability is a feature of a client's terminal beyond what its terminfo
+// entry and color profile describe.
+type Capability string
+
+const (
+	// The terminal supports the kitty keyboard protocol.
+	CapabilityKittyKeyboard Capability = "kitty-keyboard"
+	// The terminal supports synchronized output (mode 2026), which
+	// prevents it from showing partially drawn frames.
+	CapabilitySynchronizedOutput Capability = "synchronized-output"
+	// The terminal allows programs to set the clipboard with OSC 52.
+	CapabilityOSC52 Capability = "osc52"
+	// The terminal supports 24-bit color.
+	CapabilityTrueColor Capability = "truecolor"
+	// The terminal supports hyperlinks with OSC 8.
+	CapabilityHyperlinks Capability = "hyperlinks"
+)
+
+// Capabilities contains every Capability this build of cy understands.
+var Capabilities = []Capability{
+	CapabilityKittyKeyboard,
+	CapabilitySynchronizedOutput,
+	CapabilityOSC52,
+	CapabilityTrueColor,
+	CapabilityHyperlinks,
+}
+
+// Negotiate returns the capabilities in `requested` that this build of cy
+// understands, ignoring any it does not.
+func Negotiate(requested []Capability) (supported []Capability) {
+	for _, capability := range Capabilities {
+		if slices.Contains(requested, capability) {
+			supported = append(supported, capability)
+		}
+	}
+
+	return supported
+}
+
+// CheckClientVersion returns an error if a client that speaks `version` of
+// the protocol and requires a server that speaks at least `minVersion`
+// cannot connect to this server.
+func CheckClientVersion(version, minVersion int) error {
+	if version < MinVersion {
+		return fmt.Errorf(
+			"client speaks protocol version %d, but this server requires at least version %d: upgrade the client",
+			version,
+			MinVersion,
+		)
+	}
+
+	if minVersion > Version {
+		return fmt.Errorf(
+			"client requires protocol version %d, but this server only speaks version %d: run `cy upgrade` with the new binary to upgrade the server",
+			minVersion,
+			Version,
+		)
+	}
+
+	return nil
+}
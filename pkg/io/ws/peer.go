@@ -0,0 +1,38 @@
+package ws
+
+import (
+	"context"
+	"net"
+)
+
+// Peer describes the process on the other end of a Unix socket.
+type Peer struct {
+	UID int
+	GID int
+}
+
+type peerKey struct{}
+
+// withPeer stores the credentials of the process that opened `conn` in
+// `ctx` so that they are available to the Server handling it.
+func withPeer(ctx context.Context, conn net.Conn) context.Context {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return ctx
+	}
+
+	peer, err := getPeer(unixConn)
+	if err != nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, peerKey{}, peer)
+}
+
+// GetPeer gets the credentials of the process that opened the connection
+// that `ctx` belongs to. The credentials are verified by the kernel, so
+// they cannot be forged by the client.
+func GetPeer(ctx context.Context) (peer Peer, ok bool) {
+	peer, ok = ctx.Value(peerKey{}).(Peer)
+	return
+}
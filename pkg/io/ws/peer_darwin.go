@@ -0,0 +1,45 @@
+//go:build darwin
+// +build darwin
+
+package ws
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+func getPeer(conn *net.UnixConn) (peer Peer, err error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return
+	}
+
+	var cred *unix.Xucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptXucred(
+			int(fd),
+			unix.SOL_LOCAL,
+			unix.LOCAL_PEERCRED,
+		)
+	})
+	if err != nil {
+		return
+	}
+	if credErr != nil {
+		err = credErr
+		return
+	}
+
+	if cred.Ngroups == 0 {
+		err = fmt.Errorf("peer has no groups")
+		return
+	}
+
+	return Peer{
+		UID: int(cred.Uid),
+		GID: int(cred.Groups[0]),
+	}, nil
+}
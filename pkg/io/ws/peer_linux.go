@@ -0,0 +1,39 @@
+//go:build linux
+// +build linux
+
+package ws
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+func getPeer(conn *net.UnixConn) (peer Peer, err error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return
+	}
+
+	var cred *unix.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(
+			int(fd),
+			unix.SOL_SOCKET,
+			unix.SO_PEERCRED,
+		)
+	})
+	if err != nil {
+		return
+	}
+	if credErr != nil {
+		err = credErr
+		return
+	}
+
+	return Peer{
+		UID: int(cred.Uid),
+		GID: int(cred.Gid),
+	}, nil
+}
@@ -61,9 +61,22 @@ func Serve[T any](ctx context.Context, socketPath string, protocol Protocol[T],
 		return err
 	}
 
+	return ServeListener[T](ctx, l, socketPath, protocol, server)
+}
+
+// ServeListener is the same as Serve, but it accepts connections on an
+// existing Listener, such as one inherited from another process.
+func ServeListener[T any](
+	ctx context.Context,
+	l net.Listener,
+	socketPath string,
+	protocol Protocol[T],
+	server Server[T],
+) error {
 	ws := &WSServer[T]{server: server, protocol: protocol}
 	httpServer := http.Server{
-		Handler: ws,
+		Handler:     ws,
+		ConnContext: withPeer,
 	}
 
 	go func() {
@@ -4,10 +4,12 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type EchoServer struct{}
@@ -63,3 +65,36 @@ func TestServer(t *testing.T) {
 		t.Fail()
 	}
 }
+
+type PeerServer struct{}
+
+func (p *PeerServer) HandleWSClient(client Client[[]byte]) {
+	peer, ok := GetPeer(client.Ctx())
+	if !ok {
+		client.Send([]byte("none"))
+		return
+	}
+
+	client.Send([]byte(strconv.Itoa(peer.UID)))
+}
+
+func TestPeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	socketPath := filepath.Join(t.TempDir(), "socket")
+	go Serve[[]byte](ctx, socketPath, RawProtocol, &PeerServer{})
+
+	// wait for server to start up
+	time.Sleep(200 * time.Millisecond)
+
+	c, err := Connect(ctx, RawProtocol, socketPath)
+	require.NoError(t, err)
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for peer")
+	case msg := <-c.Receive():
+		require.Equal(t, strconv.Itoa(os.Getuid()), string(msg.Contents))
+	}
+}
@@ -242,6 +242,39 @@ func (v *VM) marshal(item interface{}) (result C.Janet, err error) {
 			C.janet_struct_put(struct_, key_, value_)
 		}
 		result = C.janet_wrap_struct(C.janet_struct_end(struct_))
+	case reflect.Map:
+		// Maps become structs whose keys are strings (or keywords, if
+		// the keys are Keywords)
+		if type_.Key().Kind() != reflect.String {
+			err = fmt.Errorf(
+				"unimplemented map key type: %s",
+				type_.Key().String(),
+			)
+			return
+		}
+
+		struct_ := C.janet_struct_begin(C.int(value.Len()))
+		iter := value.MapRange()
+		for iter.Next() {
+			key_, keyErr := v.marshal(iter.Key().Interface())
+			if keyErr != nil {
+				err = keyErr
+				return
+			}
+
+			value_, valueErr := v.marshal(iter.Value().Interface())
+			if valueErr != nil {
+				err = fmt.Errorf(
+					"could not marshal value '%s': %s",
+					iter.Key().String(),
+					valueErr.Error(),
+				)
+				return
+			}
+
+			C.janet_struct_put(struct_, key_, value_)
+		}
+		result = C.janet_wrap_struct(C.janet_struct_end(struct_))
 	case reflect.Array, reflect.Slice:
 		if type_.Kind() == reflect.Slice && type_.Elem().Kind() == reflect.Uint8 {
 			slice := value.Bytes()
@@ -289,6 +289,21 @@ func TestVM(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("callback returning a map", func(t *testing.T) {
+		err = vm.Callback("test-map", "", func() interface{} {
+			return map[string]string{
+				"a": "b",
+			}
+		})
+		require.NoError(t, err)
+
+		err = vm.Execute(ctx, `(assert (= "b" ((test-map) "a")))`)
+		require.NoError(t, err)
+
+		err = vm.Execute(ctx, `(assert (struct? (test-map)))`)
+		require.NoError(t, err)
+	})
+
 	t.Run("translation", func(t *testing.T) {
 		initJanet()
 		defer deInitJanet()
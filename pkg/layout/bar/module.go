@@ -26,6 +26,13 @@ type Bar struct {
 
 var _ mux.Screen = (*Bar)(nil)
 var _ L.Reusable = (*Bar)(nil)
+var _ L.Container = (*Bar)(nil)
+
+func (t *Bar) Regions() []geom.Rect {
+	t.RLock()
+	defer t.RUnlock()
+	return []geom.Rect{t.inner}
+}
 
 func (t *Bar) Kill() {
 	t.screen.Kill()
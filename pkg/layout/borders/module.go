@@ -27,6 +27,7 @@ type Borders struct {
 
 var _ mux.Screen = (*Borders)(nil)
 var _ L.Reusable = (*Borders)(nil)
+var _ L.Container = (*Borders)(nil)
 
 func (l *Borders) Apply(node L.NodeType) (bool, error) {
 	config, ok := node.(L.BorderType)
@@ -74,6 +75,12 @@ func (l *Borders) Apply(node L.NodeType) (bool, error) {
 	return true, nil
 }
 
+func (l *Borders) Regions() []geom.Rect {
+	l.RLock()
+	defer l.RUnlock()
+	return []geom.Rect{l.inner}
+}
+
 func (l *Borders) Kill() {
 	l.RLock()
 	screen := l.screen
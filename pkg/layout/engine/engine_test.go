@@ -3,16 +3,19 @@ package engine
 import (
 	"context"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/cfoust/cy/pkg/geom"
 	L "github.com/cfoust/cy/pkg/layout"
 	"github.com/cfoust/cy/pkg/layout/pane"
+	"github.com/cfoust/cy/pkg/layout/prop"
 	S "github.com/cfoust/cy/pkg/mux/screen"
 	"github.com/cfoust/cy/pkg/mux/screen/server"
 	T "github.com/cfoust/cy/pkg/mux/screen/tree"
 	"github.com/cfoust/cy/pkg/params"
+	"github.com/cfoust/cy/pkg/style"
 	"github.com/cfoust/cy/pkg/taro"
 
 	"github.com/stretchr/testify/require"
@@ -84,6 +87,245 @@ func TestClickInactivePane(t *testing.T) {
 	}, l.Get().Root)
 }
 
+func TestDragSplit(t *testing.T) {
+	size := geom.Vec2{R: 20, C: 40}
+	l := New(
+		context.Background(),
+		T.NewTree(),
+		server.New(),
+	)
+	l.Resize(size)
+
+	cells := 20
+	err := l.Set(L.New(
+		L.SplitType{
+			Cells:  &cells,
+			Border: prop.NewStatic(&style.DefaultBorder),
+			A:      L.PaneType{Attached: true},
+			B:      L.PaneType{},
+		},
+	))
+	require.NoError(t, err)
+
+	send := func(msg taro.MouseMsg) {
+		l.Send(msg)
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// The border is in the last column of screen A
+	send(taro.MouseMsg{
+		Vec2:   geom.Vec2{R: 5, C: 19},
+		Type:   taro.MousePress,
+		Button: taro.MouseLeft,
+		Down:   true,
+	})
+	send(taro.MouseMsg{
+		Vec2:   geom.Vec2{R: 5, C: 24},
+		Type:   taro.MouseMotion,
+		Button: taro.MouseLeft,
+		Down:   true,
+	})
+	send(taro.MouseMsg{
+		Vec2:   geom.Vec2{R: 5, C: 24},
+		Type:   taro.MousePress,
+		Button: taro.MouseLeft,
+	})
+
+	layout := l.Get().Root.(L.SplitType)
+	require.Equal(t, 25, *layout.Cells)
+	require.Equal(t, L.PaneType{Attached: true}, layout.A)
+
+	// Motion after the drag has ended does nothing
+	send(taro.MouseMsg{
+		Vec2:   geom.Vec2{R: 5, C: 10},
+		Type:   taro.MouseMotion,
+		Button: taro.MouseLeft,
+		Down:   true,
+	})
+	layout = l.Get().Root.(L.SplitType)
+	require.Equal(t, 25, *layout.Cells)
+}
+
+func TestZoom(t *testing.T) {
+	l := New(
+		context.Background(),
+		T.NewTree(),
+		server.New(),
+	)
+	l.Resize(geom.DEFAULT_SIZE)
+
+	cells := 30
+	layout := L.New(L.MarginsType{
+		Node: L.SplitType{
+			Cells: &cells,
+			A:     L.PaneType{},
+			B:     L.PaneType{Attached: true},
+		},
+	})
+	require.NoError(t, l.Set(layout))
+
+	require.NoError(t, l.Zoom(true))
+	require.True(t, l.IsZoomed())
+	require.Equal(t, layout, l.Get())
+	require.Equal(t, L.MarginsType{
+		Node: L.PaneType{Attached: true},
+	}, l.existing.Config)
+
+	require.NoError(t, l.Zoom(false))
+	require.Equal(t, L.PaneType{Attached: true}, l.existing.Config)
+
+	require.NoError(t, l.Unzoom())
+	require.False(t, l.IsZoomed())
+	require.Equal(t, layout.Root, l.existing.Config)
+
+	// Attaching to another pane restores the layout
+	require.NoError(t, l.Zoom(false))
+	moved := L.New(L.MarginsType{
+		Node: L.SplitType{
+			Cells: &cells,
+			A:     L.PaneType{Attached: true},
+			B:     L.PaneType{},
+		},
+	})
+	require.NoError(t, l.Set(moved))
+	require.False(t, l.IsZoomed())
+	require.Equal(t, moved.Root, l.existing.Config)
+}
+
+func TestPanes(t *testing.T) {
+	l := New(
+		context.Background(),
+		T.NewTree(),
+		server.New(),
+	)
+	size := geom.Vec2{R: 20, C: 80}
+	l.Resize(size)
+
+	cells := 30
+	require.NoError(t, l.Set(L.New(L.TabsType{
+		Tabs: []L.Tab{
+			{
+				Name: "first",
+				Node: L.SplitType{
+					A: L.PaneType{},
+					B: L.PaneType{},
+				},
+			},
+			{
+				Name:   "second",
+				Active: true,
+				Node: L.SplitType{
+					Cells: &cells,
+					A:     L.PaneType{},
+					B:     L.PaneType{Attached: true},
+				},
+			},
+		},
+	})))
+
+	// Only the panes in the active tab are visible, but their indices
+	// account for the panes in the first tab
+	panes := l.Panes()
+	require.Len(t, panes, 2)
+	require.Equal(t, 2, panes[0].Index)
+	require.Equal(t, 3, panes[1].Index)
+	require.True(t, panes[1].Config.Attached)
+
+	// The tab bar takes up the first row
+	require.Equal(t, geom.Vec2{R: 1}, panes[0].Position)
+	require.Equal(t, geom.Vec2{R: 19, C: 30}, panes[0].Size)
+	require.Equal(t, geom.Vec2{R: 1, C: 30}, panes[1].Position)
+	require.Equal(t, geom.Vec2{R: 19, C: 50}, panes[1].Size)
+
+	require.NoError(t, l.Zoom(false))
+	panes = l.Panes()
+	require.Len(t, panes, 1)
+	require.Equal(t, 3, panes[0].Index)
+	require.Equal(t, geom.Rect{Size: size}, panes[0].Rect)
+}
+
+func TestClickGrid(t *testing.T) {
+	l := New(
+		context.Background(),
+		T.NewTree(),
+		server.New(),
+	)
+	l.Resize(geom.Vec2{R: 21, C: 41})
+
+	err := l.Set(L.New(
+		L.GridType{
+			Border: prop.NewStatic(&style.DefaultBorder),
+			Nodes: []L.NodeType{
+				L.PaneType{Attached: true},
+				L.PaneType{},
+				L.PaneType{},
+			},
+		},
+	))
+	require.NoError(t, err)
+
+	// The last node takes up the whole bottom row
+	l.Send(taro.MouseMsg{
+		Vec2:   geom.Vec2{R: 15, C: 35},
+		Type:   taro.MousePress,
+		Button: taro.MouseLeft,
+	})
+	time.Sleep(500 * time.Millisecond)
+
+	require.Equal(t, []L.NodeType{
+		L.PaneType{},
+		L.PaneType{},
+		L.PaneType{Attached: true},
+	}, l.Get().Root.(L.GridType).Nodes)
+}
+
+func TestClickFloat(t *testing.T) {
+	size := geom.Vec2{R: 20, C: 40}
+	l := New(
+		context.Background(),
+		T.NewTree(),
+		server.New(),
+	)
+	l.Resize(size)
+
+	width := L.Dimension{Value: 10}
+	height := L.Dimension{Value: 50, IsPercent: true}
+	err := l.Set(L.New(
+		L.FloatType{
+			Node: L.PaneType{Attached: true},
+			Floats: []L.Float{
+				{
+					Width:  &width,
+					Height: &height,
+					Node:   L.PaneType{},
+				},
+			},
+		},
+	))
+	require.NoError(t, err)
+
+	click := func(loc geom.Vec2) {
+		l.Send(taro.MouseMsg{
+			Vec2:   loc,
+			Type:   taro.MousePress,
+			Button: taro.MouseLeft,
+		})
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	// The float is centered, so this is inside of it
+	click(geom.Vec2{R: 10, C: 20})
+	layout := l.Get().Root.(L.FloatType)
+	require.Equal(t, L.PaneType{}, layout.Node)
+	require.Equal(t, L.PaneType{Attached: true}, layout.Floats[0].Node)
+
+	// But this is not
+	click(geom.Vec2{R: 1, C: 1})
+	layout = l.Get().Root.(L.FloatType)
+	require.Equal(t, L.PaneType{Attached: true}, layout.Node)
+	require.Equal(t, L.PaneType{}, layout.Floats[0].Node)
+}
+
 func TestRemoveAttached(t *testing.T) {
 	require.Equal(t,
 		L.MarginsType{Node: L.PaneType{Attached: true}},
@@ -292,6 +534,44 @@ func TestPaneRemoval(t *testing.T) {
 	}
 }
 
+func TestZoomedPaneRemoval(t *testing.T) {
+	ctx := context.Background()
+	tree := T.NewTree()
+	l := New(ctx, tree, server.New())
+	l.Resize(geom.DEFAULT_SIZE)
+
+	pane1 := tree.Root().NewPane(ctx, pane.NewStatic(ctx, false, "foo"))
+	pane2 := tree.Root().NewPane(ctx, pane.NewStatic(ctx, false, "bar"))
+	id1, id2 := pane1.Id(), pane2.Id()
+
+	require.NoError(t, l.Set(L.New(L.MarginsType{
+		Node: L.SplitType{
+			A: L.PaneType{Attached: true, ID: &id1},
+			B: L.PaneType{ID: &id2},
+		},
+	})))
+	require.NoError(t, l.Zoom(true))
+
+	// Give the panes time to subscribe to their nodes
+	time.Sleep(500 * time.Millisecond)
+	pane1.Cancel()
+
+	// The change to the attached pane is applied to the full layout
+	expected := L.MarginsType{
+		Node: L.SplitType{
+			A: L.PaneType{Attached: true},
+			B: L.PaneType{ID: &id2},
+		},
+	}
+	require.Eventually(t, func() bool {
+		return reflect.DeepEqual(expected, l.Get().Root)
+	}, 5*time.Second, 50*time.Millisecond)
+	require.True(t, l.IsZoomed())
+
+	require.NoError(t, l.Unzoom())
+	require.Equal(t, expected, l.Get().Root)
+}
+
 func TestClickTabs(t *testing.T) {
 	size := geom.DEFAULT_SIZE
 	l := New(
@@ -434,3 +714,127 @@ func TestClickTabs(t *testing.T) {
 		},
 	}, l.Get().Root)
 }
+
+func TestDragTabs(t *testing.T) {
+	l := New(
+		context.Background(),
+		T.NewTree(),
+		server.New(),
+	)
+	l.Resize(geom.DEFAULT_SIZE)
+
+	tabs := []L.Tab{
+		{Name: "a", Active: true, Node: L.PaneType{Attached: true}},
+		{Name: "b", Node: L.PaneType{}},
+		{Name: "c", Node: L.PaneType{}},
+	}
+	require.NoError(t, l.Set(L.New(L.TabsType{Tabs: tabs})))
+
+	// Required to force the bar bounds to calculate
+	l.State()
+
+	send := func(msg taro.MouseMsg) {
+		l.Send(msg)
+		time.Sleep(500 * time.Millisecond)
+		l.State()
+	}
+
+	// Each tab is three cells wide
+	send(taro.MouseMsg{
+		Vec2:   geom.Vec2{C: 1},
+		Type:   taro.MousePress,
+		Button: taro.MouseLeft,
+		Down:   true,
+	})
+	send(taro.MouseMsg{
+		Vec2:   geom.Vec2{C: 7},
+		Type:   taro.MouseMotion,
+		Button: taro.MouseLeft,
+		Down:   true,
+	})
+	send(taro.MouseMsg{
+		Vec2:   geom.Vec2{C: 7},
+		Type:   taro.MousePress,
+		Button: taro.MouseLeft,
+	})
+
+	require.Equal(t, L.TabsType{
+		Tabs: []L.Tab{tabs[1], tabs[2], tabs[0]},
+	}, l.Get().Root)
+}
+
+func TestMiddleClickTabs(t *testing.T) {
+	l := New(
+		context.Background(),
+		T.NewTree(),
+		server.New(),
+	)
+	l.Resize(geom.DEFAULT_SIZE)
+
+	require.NoError(t, l.Set(L.New(L.TabsType{
+		Tabs: []L.Tab{
+			{Name: "a", Active: true, Node: L.PaneType{Attached: true}},
+			{Name: "b", Node: L.PaneType{}},
+		},
+	})))
+	l.State()
+
+	click := func(loc geom.Vec2) {
+		l.Send(taro.MouseMsg{
+			Vec2:   loc,
+			Type:   taro.MousePress,
+			Button: taro.MouseMiddle,
+		})
+		time.Sleep(500 * time.Millisecond)
+		l.State()
+	}
+
+	// Closing the active tab activates the next one
+	click(geom.Vec2{C: 1})
+	require.Equal(t, L.TabsType{
+		Tabs: []L.Tab{
+			{Name: "b", Active: true, Node: L.PaneType{Attached: true}},
+		},
+	}, l.Get().Root)
+
+	// The last tab cannot be closed
+	click(geom.Vec2{C: 1})
+	require.Equal(t, L.TabsType{
+		Tabs: []L.Tab{
+			{Name: "b", Active: true, Node: L.PaneType{Attached: true}},
+		},
+	}, l.Get().Root)
+}
+
+func TestTabsOverflow(t *testing.T) {
+	l := New(
+		context.Background(),
+		T.NewTree(),
+		server.New(),
+	)
+	l.Resize(geom.Vec2{R: 5, C: 10})
+
+	var tabs []L.Tab
+	for i := 0; i < 10; i++ {
+		tabs = append(tabs, L.Tab{Name: "tab", Node: L.PaneType{}})
+	}
+	tabs[5] = L.Tab{
+		Name:   "tab",
+		Active: true,
+		Node:   L.PaneType{Attached: true},
+	}
+	require.NoError(t, l.Set(L.New(L.TabsType{Tabs: tabs})))
+
+	state := l.State()
+	require.Equal(t, '<', state.Image[0][0].Char)
+	require.Equal(t, '>', state.Image[0][9].Char)
+
+	// Clicking the left indicator moves to the previous tab
+	l.Send(taro.MouseMsg{
+		Vec2:   geom.Vec2{C: 0},
+		Type:   taro.MousePress,
+		Button: taro.MouseLeft,
+	})
+	time.Sleep(500 * time.Millisecond)
+	require.Equal(t, 4, l.Get().Root.(L.TabsType).ActiveIndex())
+}
@@ -2,11 +2,13 @@ package engine
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/cfoust/cy/pkg/geom"
 	"github.com/cfoust/cy/pkg/geom/tty"
 	L "github.com/cfoust/cy/pkg/layout"
+	"github.com/cfoust/cy/pkg/layout/tabs"
 	"github.com/cfoust/cy/pkg/mux"
 	"github.com/cfoust/cy/pkg/mux/screen/server"
 	"github.com/cfoust/cy/pkg/mux/screen/tree"
@@ -117,6 +119,17 @@ type LayoutEngine struct {
 	layoutLifetime *util.Lifetime
 	screen         mux.Screen
 	existing       *screenNode
+
+	// Whether only the attached pane is being rendered. `layout` is
+	// always the full layout, even while zoomed.
+	isZoomed bool
+	// Whether the :margins, :borders, and :bar nodes around the attached
+	// pane are rendered while zoomed.
+	keepDecorations bool
+
+	// Shared by every :tabs node so that activity markers survive the
+	// node being recreated.
+	monitor *tabs.Monitor
 }
 
 var _ mux.Screen = (*LayoutEngine)(nil)
@@ -195,6 +208,26 @@ func (l *LayoutEngine) handleChange(
 	l.Lock()
 	defer l.Unlock()
 
+	// While zoomed the rendered tree only contains the attached pane (and
+	// possibly its decorations), so the change is applied to that part of
+	// the layout
+	if l.isZoomed {
+		zoomed := applyNodeChange(
+			l.existing,
+			node,
+			L.Zoom(l.layout, l.keepDecorations),
+			config,
+		)
+
+		layout := L.Unzoom(L.Copy(l.layout), zoomed, l.keepDecorations)
+		err := L.ValidateTree(layout)
+		if err != nil {
+			return err
+		}
+
+		return l.set(layout)
+	}
+
 	layout := l.layout
 
 	// If the new configuration changes the attachment point, we need to
@@ -229,9 +262,27 @@ func (l *LayoutEngine) removeAttached() error {
 }
 
 func (l *LayoutEngine) set(layout L.NodeType) error {
+	// Moving to another pane restores the full layout
+	if l.isZoomed && l.layout != nil {
+		oldIndex, oldPanes := L.AttachedIndex(l.layout)
+		newIndex, newPanes := L.AttachedIndex(layout)
+		if oldIndex != newIndex || oldPanes != newPanes {
+			l.isZoomed = false
+		}
+	}
+
+	rendered := layout
+	if l.isZoomed {
+		rendered = L.Zoom(layout, l.keepDecorations)
+		if rendered == nil {
+			l.isZoomed = false
+			rendered = layout
+		}
+	}
+
 	node, err := l.updateNode(
 		l.Ctx(),
-		layout,
+		rendered,
 		l.existing,
 	)
 	if err != nil {
@@ -288,7 +339,45 @@ func (l *LayoutEngine) Set(layout L.Layout) error {
 	return l.set(layout.Root)
 }
 
-// Get gets the Layout this LayoutEngine is rendering.
+// Zoom renders only the attached pane until Unzoom is called or the user
+// attaches to a different pane, at which point the full layout is restored.
+// If keepDecorations is true, the :margins, :borders, and :bar nodes that
+// enclose the attached pane are still rendered.
+func (l *LayoutEngine) Zoom(keepDecorations bool) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if L.Zoom(l.layout, keepDecorations) == nil {
+		return fmt.Errorf("layout has no attached pane")
+	}
+
+	l.isZoomed = true
+	l.keepDecorations = keepDecorations
+	return l.set(l.layout)
+}
+
+// Unzoom restores the full layout.
+func (l *LayoutEngine) Unzoom() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if !l.isZoomed {
+		return nil
+	}
+
+	l.isZoomed = false
+	return l.set(l.layout)
+}
+
+// IsZoomed reports whether only the attached pane is being rendered.
+func (l *LayoutEngine) IsZoomed() bool {
+	l.RLock()
+	defer l.RUnlock()
+	return l.isZoomed
+}
+
+// Get gets the Layout this LayoutEngine is rendering. While zoomed, this is
+// the full layout rather than just the attached pane.
 func (l *LayoutEngine) Get() L.Layout {
 	l.RLock()
 	layout := l.layout
@@ -296,6 +385,91 @@ func (l *LayoutEngine) Get() L.Layout {
 	return L.New(layout)
 }
 
+// PaneRegion is a pane that is visible on the screen.
+type PaneRegion struct {
+	// The area of the screen the pane occupies.
+	geom.Rect
+	// The position of the pane among all of the panes in the layout, in
+	// the same order as they appear in the layout's configuration.
+	Index  int
+	Config L.PaneType
+}
+
+// Panes returns every pane that is currently visible along with the area of
+// the screen it occupies. Panes in inactive tabs are not included. While
+// zoomed, only the attached pane is returned.
+func (l *LayoutEngine) Panes() []PaneRegion {
+	l.RLock()
+	var (
+		existing = l.existing
+		layout   = l.layout
+		isZoomed = l.isZoomed
+		size     = l.size
+	)
+	l.RUnlock()
+
+	if existing == nil {
+		return nil
+	}
+
+	panes := getRegions(existing, geom.Rect{Size: size}, 0)
+	if !isZoomed {
+		return panes
+	}
+
+	// The rendered tree only contains the attached pane, but its index
+	// should refer to the full layout
+	index, _ := L.AttachedIndex(layout)
+	for i := range panes {
+		panes[i].Index = index
+	}
+	return panes
+}
+
+// getRegions finds the visible panes inside of node, which occupies rect on
+// the screen. offset is the number of panes in the layout that come before
+// node.
+func getRegions(
+	node *screenNode,
+	rect geom.Rect,
+	offset int,
+) (panes []PaneRegion) {
+	if pane, ok := node.Config.(L.PaneType); ok {
+		return []PaneRegion{{
+			Rect:   rect,
+			Index:  offset,
+			Config: pane,
+		}}
+	}
+
+	container, ok := node.Screen.(L.Container)
+	if !ok {
+		return nil
+	}
+
+	// Only the active tab is rendered, but the panes in the tabs before
+	// it still come first in the layout
+	if tabs, ok := node.Config.(L.TabsType); ok {
+		for _, tab := range tabs.Tabs[:geom.Max(tabs.ActiveIndex(), 0)] {
+			offset += L.NumPanes(tab.Node)
+		}
+	}
+
+	regions := container.Regions()
+	for i, child := range node.Children {
+		if i >= len(regions) {
+			break
+		}
+
+		region := regions[i]
+		region.Position = region.Position.Add(rect.Position)
+		panes = append(panes, getRegions(child, region, offset)...)
+		offset += L.NumPanes(child.Config)
+	}
+
+	return panes
+}
+
 type Setting func(*LayoutEngine)
 
 func WithParams(params *params.Parameters) Setting {
@@ -337,6 +511,7 @@ func New(
 		params:          params.New(),
 		throttle:        t,
 		log:             log.Logger,
+		monitor:         tabs.NewMonitor(lifetime.Ctx(), tree),
 	}
 
 	for _, setting := range settings {
@@ -7,10 +7,14 @@ import (
 	L "github.com/cfoust/cy/pkg/layout"
 	"github.com/cfoust/cy/pkg/layout/bar"
 	"github.com/cfoust/cy/pkg/layout/borders"
+	"github.com/cfoust/cy/pkg/layout/float"
+	"github.com/cfoust/cy/pkg/layout/grid"
 	"github.com/cfoust/cy/pkg/layout/margins"
+	"github.com/cfoust/cy/pkg/layout/nsplit"
 	"github.com/cfoust/cy/pkg/layout/pane"
 	"github.com/cfoust/cy/pkg/layout/split"
 	"github.com/cfoust/cy/pkg/layout/tabs"
+	"github.com/cfoust/cy/pkg/mux"
 	"github.com/cfoust/cy/pkg/util"
 
 	"github.com/rs/zerolog/log"
@@ -42,6 +46,12 @@ func (l *LayoutEngine) createNode(
 		err = l.createTabs(node, config)
 	case L.BarType:
 		err = l.createBar(node, config)
+	case L.FloatType:
+		err = l.createFloat(node, config)
+	case L.NSplitType:
+		err = l.createNSplit(node, config)
+	case L.GridType:
+		err = l.createGrid(node, config)
 	default:
 		err = fmt.Errorf("unimplemented screen")
 	}
@@ -155,6 +165,39 @@ func (l *LayoutEngine) updateNode(
 				Node:   current.Children[0],
 			},
 		)
+	case L.FloatType:
+		updates = append(updates,
+			updateNode{
+				Config: node.Node,
+				Node:   current.Children[0],
+			},
+		)
+		for i, float := range node.Floats {
+			updates = append(updates,
+				updateNode{
+					Config: float.Node,
+					Node:   current.Children[i+1],
+				},
+			)
+		}
+	case L.NSplitType:
+		for i, child := range node.Nodes {
+			updates = append(updates,
+				updateNode{
+					Config: child.Node,
+					Node:   current.Children[i],
+				},
+			)
+		}
+	case L.GridType:
+		for i, child := range node.Nodes {
+			updates = append(updates,
+				updateNode{
+					Config: child,
+					Node:   current.Children[i],
+				},
+			)
+		}
 	}
 
 	// If any of the node's children cannot be reused, we need to remake
@@ -285,6 +328,7 @@ func (l *LayoutEngine) createTabs(
 	tabs := tabs.New(
 		node.Ctx(),
 		innerNode.Screen,
+		l.monitor,
 	)
 
 	node.Screen = tabs
@@ -314,6 +358,104 @@ func (l *LayoutEngine) createBar(
 	return nil
 }
 
+func (l *LayoutEngine) createFloat(
+	node *screenNode,
+	config L.FloatType,
+) error {
+	baseNode, err := l.createNode(
+		node.Ctx(),
+		config.Node,
+	)
+	if err != nil {
+		return err
+	}
+
+	children := []*screenNode{baseNode}
+	var floats []mux.Screen
+	for _, floatConfig := range config.Floats {
+		floatNode, err := l.createNode(
+			node.Ctx(),
+			floatConfig.Node,
+		)
+		if err != nil {
+			return err
+		}
+
+		children = append(children, floatNode)
+		floats = append(floats, floatNode.Screen)
+	}
+
+	float := float.New(
+		node.Ctx(),
+		baseNode.Screen,
+		floats,
+	)
+
+	node.Screen = float
+	node.Children = children
+	return nil
+}
+
+// createChildren creates a screenNode for each of the provided layout nodes.
+func (l *LayoutEngine) createChildren(
+	node *screenNode,
+	configs []L.NodeType,
+) (children []*screenNode, screens []mux.Screen, err error) {
+	for _, config := range configs {
+		child, err := l.createNode(
+			node.Ctx(),
+			config,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		children = append(children, child)
+		screens = append(screens, child.Screen)
+	}
+
+	return children, screens, nil
+}
+
+func (l *LayoutEngine) createNSplit(
+	node *screenNode,
+	config L.NSplitType,
+) error {
+	var configs []L.NodeType
+	for _, child := range config.Nodes {
+		configs = append(configs, child.Node)
+	}
+
+	children, screens, err := l.createChildren(node, configs)
+	if err != nil {
+		return err
+	}
+
+	node.Screen = nsplit.New(
+		node.Ctx(),
+		screens,
+	)
+	node.Children = children
+	return nil
+}
+
+func (l *LayoutEngine) createGrid(
+	node *screenNode,
+	config L.GridType,
+) error {
+	children, screens, err := l.createChildren(node, config.Nodes)
+	if err != nil {
+		return err
+	}
+
+	node.Screen = grid.New(
+		node.Ctx(),
+		screens,
+	)
+	node.Children = children
+	return nil
+}
+
 // applyNodeChange replaces the configuration of the target node with
 // newConfig. This is only used to allow nodes to change their own
 // configurations in response to user input (for now, just mouse events.)
@@ -380,6 +522,49 @@ func applyNodeChange(
 			newConfig,
 		)
 		return currentConfig
+	case L.FloatType:
+		currentConfig.Node = applyNodeChange(
+			current.Children[0],
+			target,
+			currentConfig.Node,
+			newConfig,
+		)
+
+		floats := append([]L.Float{}, currentConfig.Floats...)
+		for i, float := range floats {
+			floats[i].Node = applyNodeChange(
+				current.Children[i+1],
+				target,
+				float.Node,
+				newConfig,
+			)
+		}
+		currentConfig.Floats = floats
+		return currentConfig
+	case L.NSplitType:
+		nodes := append([]L.SplitNode{}, currentConfig.Nodes...)
+		for i, child := range nodes {
+			nodes[i].Node = applyNodeChange(
+				current.Children[i],
+				target,
+				child.Node,
+				newConfig,
+			)
+		}
+		currentConfig.Nodes = nodes
+		return currentConfig
+	case L.GridType:
+		nodes := append([]L.NodeType{}, currentConfig.Nodes...)
+		for i, child := range nodes {
+			nodes[i] = applyNodeChange(
+				current.Children[i],
+				target,
+				child,
+				newConfig,
+			)
+		}
+		currentConfig.Nodes = nodes
+		return currentConfig
 	}
 
 	return currentConfig
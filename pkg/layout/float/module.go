@@ -0,0 +1,335 @@
+package float
+
+import (
+	"context"
+
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/geom/tty"
+	L "github.com/cfoust/cy/pkg/layout"
+	"github.com/cfoust/cy/pkg/layout/prop"
+	"github.com/cfoust/cy/pkg/mux"
+	"github.com/cfoust/cy/pkg/taro"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sasha-s/go-deadlock"
+)
+
+const (
+	// The size of a float along an axis if none was provided.
+	DEFAULT_PERCENT = 80
+)
+
+// Float renders a base Screen and shows other Screens floating on top of it.
+// The floating Screens do not affect the size of the base Screen.
+type Float struct {
+	*L.Computable
+	deadlock.RWMutex
+	*mux.UpdatePublisher
+	render *taro.Renderer
+
+	base   mux.Screen
+	floats []mux.Screen
+
+	size geom.Size
+	// The area occupied by each float, including its border.
+	outer []geom.Rect
+	// The area occupied by the Screen of each float.
+	inner  []geom.Rect
+	config L.FloatType
+}
+
+var _ mux.Screen = (*Float)(nil)
+var _ L.Reusable = (*Float)(nil)
+var _ L.Container = (*Float)(nil)
+
+func (f *Float) Apply(node L.NodeType) (bool, error) {
+	config, ok := node.(L.FloatType)
+	if !ok {
+		return false, nil
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	// Floats cannot be added or removed without recreating the node
+	if len(config.Floats) != len(f.floats) {
+		return false, nil
+	}
+
+	f.config = config
+
+	for _, float := range config.Floats {
+		layout := L.New(float.Node)
+		for _, prop := range []prop.Presettable{
+			float.Border,
+			float.BorderFg,
+			float.BorderBg,
+		} {
+			prop.Preset(
+				f.Ctx(),
+				f.Context.Context(),
+				&layout,
+			)
+			prop.SetLogger(f.Logger)
+		}
+	}
+
+	return true, f.recalculate()
+}
+
+func (f *Float) Kill() {
+	f.RLock()
+	var (
+		base   = f.base
+		floats = f.floats
+	)
+	f.RUnlock()
+
+	base.Kill()
+	for _, float := range floats {
+		float.Kill()
+	}
+}
+
+// hasBorder reports whether the float should be surrounded by a border.
+func hasBorder(config L.Float) bool {
+	value, ok := config.Border.GetPreset()
+	return ok && !value.None()
+}
+
+func (f *Float) State() *tty.State {
+	f.Lock()
+	defer f.Unlock()
+
+	var (
+		size   = f.size
+		outer  = f.outer
+		inner  = f.inner
+		config = f.config
+	)
+
+	state := tty.New(size)
+	baseState := f.base.State()
+	image.Copy(geom.Vec2{}, state.Image, baseState.Image)
+	state.Cursor = baseState.Cursor
+	state.CursorVisible = baseState.CursorVisible
+
+	for i, screen := range f.floats {
+		if i >= len(config.Floats) || i >= len(outer) {
+			break
+		}
+
+		float := config.Floats[i]
+		rect := outer[i]
+
+		// Hide anything underneath the float
+		image.Copy(rect.Position, state.Image, image.New(rect.Size))
+
+		if rect.Contains(state.Cursor.Vec2) {
+			state.CursorVisible = false
+		}
+
+		if hasBorder(float) {
+			borderStyle, _ := float.Border.GetPreset()
+			boxStyle := f.render.NewStyle().
+				Border(borderStyle.Border).
+				BorderForeground(lipgloss.Color("7")).
+				Width(geom.Max(rect.Size.C-2, 0)).
+				Height(geom.Max(rect.Size.R-2, 0))
+
+			if value, ok := float.BorderFg.GetPreset(); ok {
+				boxStyle = boxStyle.BorderForeground(value.Color)
+			}
+
+			if value, ok := float.BorderBg.GetPreset(); ok {
+				boxStyle = boxStyle.BorderBackground(value.Color)
+			}
+
+			box := image.New(rect.Size)
+			f.render.RenderAt(box, 0, 0, boxStyle.Render(""))
+			image.Copy(rect.Position, state.Image, box)
+		}
+
+		floatState := screen.State()
+		position := inner[i].Position
+		image.Copy(position, state.Image, floatState.Image)
+
+		if floatState.CursorVisible {
+			cursor := floatState.Cursor
+			cursor.R += position.R
+			cursor.C += position.C
+			state.Cursor = cursor
+			state.CursorVisible = true
+		}
+	}
+
+	return state
+}
+
+// Send passes mouse events to the topmost Screen beneath the mouse cursor and
+// all other events to every Screen.
+func (f *Float) Send(msg mux.Msg) {
+	f.RLock()
+	var (
+		base   = f.base
+		floats = f.floats
+		outer  = f.outer
+		inner  = f.inner
+	)
+	f.RUnlock()
+
+	mouseMsg, ok := msg.(taro.MouseMsg)
+	if !ok {
+		base.Send(msg)
+		for _, float := range floats {
+			float.Send(msg)
+		}
+		return
+	}
+
+	for i := len(floats) - 1; i >= 0; i-- {
+		if i >= len(outer) || !outer[i].Contains(mouseMsg.Vec2) {
+			continue
+		}
+
+		// Clicks on the border do nothing
+		if !inner[i].Contains(mouseMsg.Vec2) {
+			return
+		}
+
+		floats[i].Send(taro.TranslateMouseMessage(
+			msg,
+			-inner[i].Position.C,
+			-inner[i].Position.R,
+		))
+		return
+	}
+
+	base.Send(msg)
+}
+
+func (f *Float) poll(ctx context.Context, screen mux.Screen) {
+	updates := screen.Subscribe(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-updates.Recv():
+			if _, ok := event.(L.NodeChangeEvent); ok {
+				continue
+			}
+			f.Publish(event)
+		}
+	}
+}
+
+// getOffset determines the position of a float along an axis.
+func getOffset(position *L.Dimension, size, total int) int {
+	offset := (total - size) / 2
+	if position != nil {
+		offset = position.Cells(total)
+	}
+
+	return geom.Clamp(offset, 0, geom.Max(total-size, 0))
+}
+
+// getLength determines the size of a float along an axis.
+func getLength(length *L.Dimension, total int) int {
+	cells := (DEFAULT_PERCENT * total) / 100
+	if length != nil {
+		cells = length.Cells(total)
+	}
+
+	return geom.Clamp(cells, 1, geom.Max(total, 1))
+}
+
+func (f *Float) Regions() []geom.Rect {
+	f.RLock()
+	defer f.RUnlock()
+	return append([]geom.Rect{{Size: f.size}}, f.inner...)
+}
+
+func (f *Float) recalculate() error {
+	size := f.size
+	config := f.config
+
+	err := f.base.Resize(size)
+	if err != nil {
+		return err
+	}
+
+	var outer, inner []geom.Rect
+	for i, screen := range f.floats {
+		if i >= len(config.Floats) {
+			break
+		}
+
+		float := config.Floats[i]
+
+		rect := geom.Rect{
+			Size: geom.Vec2{
+				R: getLength(float.Height, size.R),
+				C: getLength(float.Width, size.C),
+			},
+		}
+		rect.Position = geom.Vec2{
+			R: getOffset(float.Row, rect.Size.R, size.R),
+			C: getOffset(float.Col, rect.Size.C, size.C),
+		}
+
+		innerRect := rect
+		if hasBorder(float) {
+			innerRect = geom.Rect{
+				Position: rect.Position.Add(geom.UnitVec2),
+				Size: geom.Vec2{
+					R: geom.Max(rect.Size.R-2, 1),
+					C: geom.Max(rect.Size.C-2, 1),
+				},
+			}
+		}
+
+		outer = append(outer, rect)
+		inner = append(inner, innerRect)
+
+		err := screen.Resize(innerRect.Size)
+		if err != nil {
+			return err
+		}
+	}
+
+	f.outer = outer
+	f.inner = inner
+	return nil
+}
+
+func (f *Float) Resize(size geom.Size) error {
+	f.Lock()
+	defer f.Unlock()
+	f.size = size
+	return f.recalculate()
+}
+
+func New(
+	ctx context.Context,
+	base mux.Screen,
+	floats []mux.Screen,
+) *Float {
+	c := L.NewComputable(ctx)
+	float := &Float{
+		Computable:      c,
+		UpdatePublisher: mux.NewPublisher(),
+		render:          taro.NewRenderer(),
+		size:            geom.DEFAULT_SIZE,
+		base:            base,
+		floats:          floats,
+	}
+
+	go float.poll(float.Ctx(), base)
+	for _, screen := range floats {
+		go float.poll(float.Ctx(), screen)
+	}
+
+	return float
+}
@@ -0,0 +1,142 @@
+package grid
+
+import (
+	"context"
+
+	"github.com/cfoust/cy/pkg/geom"
+	L "github.com/cfoust/cy/pkg/layout"
+	"github.com/cfoust/cy/pkg/layout/nsplit"
+	"github.com/cfoust/cy/pkg/layout/prop"
+	"github.com/cfoust/cy/pkg/mux"
+)
+
+// Grid arranges Screens into rows with a fixed number of Screens in each.
+// Every row is given the same height and every Screen in a row is given the
+// same width, so the Screens in a short final row are wider than the others.
+type Grid struct {
+	*nsplit.Tiles
+}
+
+var _ mux.Screen = (*Grid)(nil)
+var _ L.Reusable = (*Grid)(nil)
+
+func (g *Grid) Apply(node L.NodeType) (bool, error) {
+	config, ok := node.(L.GridType)
+	if !ok {
+		return false, nil
+	}
+
+	// Nodes cannot be added or removed without recreating the node
+	if len(config.Nodes) != g.NumScreens() {
+		return false, nil
+	}
+
+	var layouts []*L.Layout
+	for _, child := range config.Nodes {
+		layout := L.New(child)
+		layouts = append(layouts, &layout)
+	}
+
+	for _, prop := range []prop.Presettable{
+		config.Border,
+		config.BorderFg,
+		config.BorderBg,
+	} {
+		prop.Preset(
+			g.Ctx(),
+			g.Context.Context(),
+			layouts,
+		)
+		prop.SetLogger(g.Logger)
+	}
+
+	return true, g.Configure(
+		config.Border,
+		config.BorderFg,
+		config.BorderBg,
+		arrange(len(config.Nodes), config.GetCols()),
+	)
+}
+
+// evenly returns a list of n ones, which gives every item the same weight.
+func evenly(n int) []int {
+	weights := make([]int, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
+
+// arrange lays out numNodes nodes in rows of cols nodes each.
+func arrange(numNodes, cols int) nsplit.Arranger {
+	numRows := (numNodes + cols - 1) / cols
+
+	return func(size geom.Size, hasBorder bool) (
+		rects []geom.Rect,
+		lines []nsplit.Line,
+	) {
+		border := 0
+		if hasBorder {
+			border = 1
+		}
+
+		heights := nsplit.Distribute(
+			size.R-border*(numRows-1),
+			evenly(numRows),
+			nil,
+		)
+
+		row := 0
+		for i, height := range heights {
+			numCols := geom.Min(cols, numNodes-i*cols)
+			widths := nsplit.Distribute(
+				size.C-border*(numCols-1),
+				evenly(numCols),
+				nil,
+			)
+
+			col := 0
+			for j, width := range widths {
+				rects = append(rects, geom.Rect{
+					Position: geom.Vec2{R: row, C: col},
+					Size:     geom.Vec2{R: height, C: width},
+				})
+				col += width
+
+				if !hasBorder || j == len(widths)-1 {
+					continue
+				}
+
+				lines = append(lines, nsplit.Line{
+					Rect: geom.Rect{
+						Position: geom.Vec2{R: row, C: col},
+						Size:     geom.Vec2{R: height, C: 1},
+					},
+					IsColumn: true,
+				})
+				col++
+			}
+			row += height
+
+			if !hasBorder || i == len(heights)-1 {
+				continue
+			}
+
+			lines = append(lines, nsplit.Line{
+				Rect: geom.Rect{
+					Position: geom.Vec2{R: row},
+					Size:     geom.Vec2{R: 1, C: size.C},
+				},
+			})
+			row++
+		}
+
+		return
+	}
+}
+
+func New(ctx context.Context, screens []mux.Screen) *Grid {
+	return &Grid{
+		Tiles: nsplit.NewTiles(ctx, screens),
+	}
+}
@@ -0,0 +1,182 @@
+package layout
+
+import (
+	"reflect"
+
+	"github.com/cfoust/cy/pkg/layout/prop"
+)
+
+// History records the layouts replaced by changes to a layout so that those
+// changes can be undone and redone.
+type History struct {
+	undo []Layout
+	redo []Layout
+}
+
+// Record adds previous, the layout replaced by a change, to the History.
+// Changes that were undone can no longer be redone. Only the last limit
+// changes are kept.
+func (h *History) Record(previous Layout, limit int) {
+	h.redo = nil
+
+	if limit <= 0 {
+		h.undo = nil
+		return
+	}
+
+	h.undo = append(h.undo, previous)
+	if len(h.undo) > limit {
+		h.undo = h.undo[len(h.undo)-limit:]
+	}
+}
+
+// Undo returns the layout from before the most recent change. current is
+// the layout as it is now, which Redo will return.
+func (h *History) Undo(current Layout) (layout Layout, ok bool) {
+	if len(h.undo) == 0 {
+		return
+	}
+
+	layout = h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, current)
+	return layout, true
+}
+
+// Redo returns the layout from before the most recent call to Undo. current
+// is the layout as it is now, which Undo will return.
+func (h *History) Redo(current Layout) (layout Layout, ok bool) {
+	if len(h.redo) == 0 {
+		return
+	}
+
+	layout = h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, current)
+	return layout, true
+}
+
+// normalizeProp returns a copy of p that can be compared using
+// reflect.DeepEqual. Dynamic properties are all considered to be the same
+// because Janet functions cannot be compared.
+func normalizeProp[T any](p *prop.Prop[T], ignoreCosmetic bool) *prop.Prop[T] {
+	if p == nil || ignoreCosmetic {
+		return nil
+	}
+
+	if value, ok := p.Static(); ok {
+		return prop.NewStatic(value)
+	}
+
+	return &prop.Prop[T]{}
+}
+
+// normalize returns a copy of node without anything that does not change
+// which panes are shown or where they are shown. The attached pane and the
+// active tab are always removed. If ignoreCosmetic is true, so are
+// properties that only change how the layout looks, such as titles, tab
+// names, colors, and border styles.
+func normalize(node NodeType, ignoreCosmetic bool) NodeType {
+	switch node := node.(type) {
+	case PaneType:
+		node.Attached = false
+		return node
+	case SplitType:
+		node.Border = normalizeProp(node.Border, ignoreCosmetic)
+		node.BorderFg = normalizeProp(node.BorderFg, ignoreCosmetic)
+		node.BorderBg = normalizeProp(node.BorderBg, ignoreCosmetic)
+		node.A = normalize(node.A, ignoreCosmetic)
+		node.B = normalize(node.B, ignoreCosmetic)
+		return node
+	case MarginsType:
+		if ignoreCosmetic {
+			node.Frame = nil
+		}
+		node.Border = normalizeProp(node.Border, ignoreCosmetic)
+		node.BorderFg = normalizeProp(node.BorderFg, ignoreCosmetic)
+		node.BorderBg = normalizeProp(node.BorderBg, ignoreCosmetic)
+		node.Node = normalize(node.Node, ignoreCosmetic)
+		return node
+	case BorderType:
+		node.Title = normalizeProp(node.Title, ignoreCosmetic)
+		node.TitleBottom = normalizeProp(node.TitleBottom, ignoreCosmetic)
+		node.Border = normalizeProp(node.Border, ignoreCosmetic)
+		node.BorderFg = normalizeProp(node.BorderFg, ignoreCosmetic)
+		node.BorderBg = normalizeProp(node.BorderBg, ignoreCosmetic)
+		node.Node = normalize(node.Node, ignoreCosmetic)
+		return node
+	case BarType:
+		node.Text = normalizeProp(node.Text, ignoreCosmetic)
+		node.Node = normalize(node.Node, ignoreCosmetic)
+		return node
+	case TabsType:
+		node.ActiveFg = normalizeProp(node.ActiveFg, ignoreCosmetic)
+		node.ActiveBg = normalizeProp(node.ActiveBg, ignoreCosmetic)
+		node.InactiveFg = normalizeProp(node.InactiveFg, ignoreCosmetic)
+		node.InactiveBg = normalizeProp(node.InactiveBg, ignoreCosmetic)
+		node.Bg = normalizeProp(node.Bg, ignoreCosmetic)
+		if ignoreCosmetic {
+			node.Activity = false
+		}
+
+		var tabs []Tab
+		for _, tab := range node.Tabs {
+			tab.Active = false
+			if ignoreCosmetic {
+				tab.Name = ""
+			}
+			tab.Node = normalize(tab.Node, ignoreCosmetic)
+			tabs = append(tabs, tab)
+		}
+		node.Tabs = tabs
+		return node
+	case FloatType:
+		var floats []Float
+		for _, float := range node.Floats {
+			float.Border = normalizeProp(float.Border, ignoreCosmetic)
+			float.BorderFg = normalizeProp(float.BorderFg, ignoreCosmetic)
+			float.BorderBg = normalizeProp(float.BorderBg, ignoreCosmetic)
+			float.Node = normalize(float.Node, ignoreCosmetic)
+			floats = append(floats, float)
+		}
+		node.Floats = floats
+		node.Node = normalize(node.Node, ignoreCosmetic)
+		return node
+	case NSplitType:
+		node.Border = normalizeProp(node.Border, ignoreCosmetic)
+		node.BorderFg = normalizeProp(node.BorderFg, ignoreCosmetic)
+		node.BorderBg = normalizeProp(node.BorderBg, ignoreCosmetic)
+
+		var nodes []SplitNode
+		for _, child := range node.Nodes {
+			child.Node = normalize(child.Node, ignoreCosmetic)
+			nodes = append(nodes, child)
+		}
+		node.Nodes = nodes
+		return node
+	case GridType:
+		node.Border = normalizeProp(node.Border, ignoreCosmetic)
+		node.BorderFg = normalizeProp(node.BorderFg, ignoreCosmetic)
+		node.BorderBg = normalizeProp(node.BorderBg, ignoreCosmetic)
+
+		var nodes []NodeType
+		for _, child := range node.Nodes {
+			nodes = append(nodes, normalize(child, ignoreCosmetic))
+		}
+		node.Nodes = nodes
+		return node
+	}
+
+	return node
+}
+
+// IsEquivalent reports whether a and b show the same panes in the same
+// places. Which pane is attached and which tab is active are not compared.
+// If ignoreCosmetic is true, neither are properties that only change how the
+// layout looks, such as titles, tab names, colors, and border styles.
+func IsEquivalent(a, b Layout, ignoreCosmetic bool) bool {
+	return reflect.DeepEqual(
+		normalize(a.Root, ignoreCosmetic),
+		normalize(b.Root, ignoreCosmetic),
+	)
+}
@@ -2,6 +2,8 @@ package layout
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/cfoust/cy/pkg/janet"
 	"github.com/cfoust/cy/pkg/layout/prop"
@@ -16,10 +18,59 @@ var (
 	KEYWORD_BORDERS = janet.Keyword("borders")
 	KEYWORD_TABS    = janet.Keyword("tabs")
 	KEYWORD_BAR     = janet.Keyword("bar")
+	KEYWORD_FLOAT   = janet.Keyword("float")
+	KEYWORD_NSPLIT  = janet.Keyword("nsplit")
+	KEYWORD_GRID    = janet.Keyword("grid")
 
 	defaultBorder = prop.NewStatic(&style.DefaultBorder)
 )
 
+var _ janet.Unmarshalable = (*Dimension)(nil)
+
+func (d *Dimension) UnmarshalJanet(value *janet.Value) error {
+	var cells int
+	if err := value.Unmarshal(&cells); err == nil {
+		d.Value = cells
+		d.IsPercent = false
+		return nil
+	}
+
+	var str string
+	if err := value.Unmarshal(&str); err != nil {
+		return fmt.Errorf(
+			"dimension must be a number of cells or a percentage",
+		)
+	}
+
+	percent, ok := strings.CutSuffix(str, "%")
+	if !ok {
+		return fmt.Errorf("invalid dimension: %s", str)
+	}
+
+	parsed, err := strconv.Atoi(percent)
+	if err != nil {
+		return fmt.Errorf("invalid dimension: %s", str)
+	}
+
+	d.Value = parsed
+	d.IsPercent = true
+	return nil
+}
+
+var _ janet.Marshalable = (*Dimension)(nil)
+
+func (d *Dimension) MarshalJanet() interface{} {
+	if d == nil {
+		return nil
+	}
+
+	if d.IsPercent {
+		return fmt.Sprintf("%d%%", d.Value)
+	}
+
+	return d.Value
+}
+
 type nodeType struct {
 	Type janet.Keyword
 }
@@ -198,6 +249,7 @@ func unmarshalNode(value *janet.Value) (NodeType, error) {
 			InactiveFg, InactiveBg *prop.Color
 			Bg                     *prop.Color
 			Bottom                 *bool
+			Activity               *bool
 			Tabs                   []tabArg
 		}
 		args := tabsArgs{}
@@ -218,6 +270,10 @@ func unmarshalNode(value *janet.Value) (NodeType, error) {
 			type_.Bottom = *args.Bottom
 		}
 
+		if args.Activity != nil {
+			type_.Activity = *args.Activity
+		}
+
 		for i, tab := range args.Tabs {
 			newTab := Tab{}
 			if tab.Active != nil {
@@ -273,6 +329,141 @@ func unmarshalNode(value *janet.Value) (NodeType, error) {
 			type_.Bottom = *args.Bottom
 		}
 
+		return type_, nil
+	case KEYWORD_FLOAT:
+		type floatArg struct {
+			Row, Col      *Dimension
+			Width, Height *Dimension
+			Border        *prop.Border
+			BorderFg      *prop.Color
+			BorderBg      *prop.Color
+			Node          *janet.Value
+		}
+		type floatArgs struct {
+			Node   *janet.Value
+			Floats []floatArg
+		}
+		args := floatArgs{}
+		err = value.Unmarshal(&args)
+		if err != nil {
+			return nil, err
+		}
+
+		node, err := unmarshalNode(args.Node)
+		if err != nil {
+			return nil, err
+		}
+
+		type_ := FloatType{
+			Node: node,
+		}
+
+		for i, float := range args.Floats {
+			node, err := unmarshalNode(float.Node)
+			if err != nil {
+				return nil, fmt.Errorf("float %d invalid: %s", i, err)
+			}
+
+			newFloat := Float{
+				Row:      float.Row,
+				Col:      float.Col,
+				Width:    float.Width,
+				Height:   float.Height,
+				Border:   float.Border,
+				BorderFg: float.BorderFg,
+				BorderBg: float.BorderBg,
+				Node:     node,
+			}
+
+			if newFloat.Border == nil {
+				newFloat.Border = defaultBorder
+			}
+
+			type_.Floats = append(type_.Floats, newFloat)
+		}
+
+		return type_, nil
+	case KEYWORD_NSPLIT:
+		type splitNodeArg struct {
+			Weight *int
+			Min    *int
+			Node   *janet.Value
+		}
+		type nsplitArgs struct {
+			Vertical *bool
+			Border   *prop.Border
+			BorderFg *prop.Color
+			BorderBg *prop.Color
+			Nodes    []splitNodeArg
+		}
+		args := nsplitArgs{}
+		err = value.Unmarshal(&args)
+		if err != nil {
+			return nil, err
+		}
+
+		type_ := NSplitType{
+			Border:   args.Border,
+			BorderFg: args.BorderFg,
+			BorderBg: args.BorderBg,
+		}
+
+		if args.Border == nil {
+			type_.Border = defaultBorder
+		}
+
+		if args.Vertical != nil {
+			type_.Vertical = *args.Vertical
+		}
+
+		for i, child := range args.Nodes {
+			node, err := unmarshalNode(child.Node)
+			if err != nil {
+				return nil, fmt.Errorf("node %d invalid: %s", i, err)
+			}
+
+			type_.Nodes = append(type_.Nodes, SplitNode{
+				Weight: child.Weight,
+				Min:    child.Min,
+				Node:   node,
+			})
+		}
+
+		return type_, nil
+	case KEYWORD_GRID:
+		type gridArgs struct {
+			Cols     *int
+			Border   *prop.Border
+			BorderFg *prop.Color
+			BorderBg *prop.Color
+			Nodes    []*janet.Value
+		}
+		args := gridArgs{}
+		err = value.Unmarshal(&args)
+		if err != nil {
+			return nil, err
+		}
+
+		type_ := GridType{
+			Cols:     args.Cols,
+			Border:   args.Border,
+			BorderFg: args.BorderFg,
+			BorderBg: args.BorderBg,
+		}
+
+		if args.Border == nil {
+			type_.Border = defaultBorder
+		}
+
+		for i, child := range args.Nodes {
+			node, err := unmarshalNode(child)
+			if err != nil {
+				return nil, fmt.Errorf("node %d invalid: %s", i, err)
+			}
+
+			type_.Nodes = append(type_.Nodes, node)
+		}
+
 		return type_, nil
 	}
 
@@ -381,6 +572,7 @@ func marshalNode(node NodeType) interface{} {
 			InactiveFg, InactiveBg *prop.Color
 			Bg                     *prop.Color
 			Bottom                 bool
+			Activity               *bool
 			Tabs                   []tabArg
 		}{
 			Type:       KEYWORD_TABS,
@@ -392,6 +584,12 @@ func marshalNode(node NodeType) interface{} {
 			Bottom:     node.Bottom,
 		}
 
+		// Omitted unless enabled so that existing layouts round-trip
+		// unchanged
+		if node.Activity {
+			type_.Activity = &node.Activity
+		}
+
 		for _, tab := range node.Tabs {
 			type_.Tabs = append(
 				type_.Tabs,
@@ -416,6 +614,95 @@ func marshalNode(node NodeType) interface{} {
 			Bottom: node.Bottom,
 			Node:   marshalNode(node.Node),
 		}
+	case FloatType:
+		type floatArg struct {
+			Row, Col      *Dimension
+			Width, Height *Dimension
+			Border        *prop.Border
+			BorderFg      *prop.Color
+			BorderBg      *prop.Color
+			Node          interface{}
+		}
+		type_ := struct {
+			Type   janet.Keyword
+			Node   interface{}
+			Floats []floatArg
+		}{
+			Type: KEYWORD_FLOAT,
+			Node: marshalNode(node.Node),
+		}
+
+		for _, float := range node.Floats {
+			type_.Floats = append(
+				type_.Floats,
+				floatArg{
+					Row:      float.Row,
+					Col:      float.Col,
+					Width:    float.Width,
+					Height:   float.Height,
+					Border:   float.Border,
+					BorderFg: float.BorderFg,
+					BorderBg: float.BorderBg,
+					Node:     marshalNode(float.Node),
+				},
+			)
+		}
+
+		return type_
+	case NSplitType:
+		type splitNodeArg struct {
+			Weight *int
+			Min    *int
+			Node   interface{}
+		}
+		type_ := struct {
+			Type     janet.Keyword
+			Vertical bool
+			Border   *prop.Border
+			BorderFg *prop.Color
+			BorderBg *prop.Color
+			Nodes    []splitNodeArg
+		}{
+			Type:     KEYWORD_NSPLIT,
+			Vertical: node.Vertical,
+			Border:   node.Border,
+			BorderFg: node.BorderFg,
+			BorderBg: node.BorderBg,
+		}
+
+		for _, child := range node.Nodes {
+			type_.Nodes = append(
+				type_.Nodes,
+				splitNodeArg{
+					Weight: child.Weight,
+					Min:    child.Min,
+					Node:   marshalNode(child.Node),
+				},
+			)
+		}
+
+		return type_
+	case GridType:
+		type_ := struct {
+			Type     janet.Keyword
+			Cols     *int
+			Border   *prop.Border
+			BorderFg *prop.Color
+			BorderBg *prop.Color
+			Nodes    []interface{}
+		}{
+			Type:     KEYWORD_GRID,
+			Cols:     node.Cols,
+			Border:   node.Border,
+			BorderFg: node.BorderFg,
+			BorderBg: node.BorderBg,
+		}
+
+		for _, child := range node.Nodes {
+			type_.Nodes = append(type_.Nodes, marshalNode(child))
+		}
+
+		return type_
 	}
 	return nil
 }
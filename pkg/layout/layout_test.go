@@ -3,6 +3,9 @@ package layout
 import (
 	"testing"
 
+	"github.com/cfoust/cy/pkg/layout/prop"
+	"github.com/cfoust/cy/pkg/mux/screen/tree"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -88,3 +91,241 @@ func TestAttachFirst(t *testing.T) {
 		},
 	}))
 }
+
+func TestRemoveFloat(t *testing.T) {
+	require.Equal(t, FloatType{
+		Node: PaneType{Attached: true},
+		Floats: []Float{
+			{Node: PaneType{}},
+		},
+	}, RemoveAttached(FloatType{
+		Node: PaneType{},
+		Floats: []Float{
+			{Node: PaneType{}},
+			{Node: PaneType{Attached: true}},
+		},
+	}))
+
+	// The topmost float replaces the base node
+	require.Equal(t, FloatType{
+		Node:   PaneType{Attached: true},
+		Floats: []Float{},
+	}, RemoveAttached(FloatType{
+		Node: PaneType{Attached: true},
+		Floats: []Float{
+			{Node: PaneType{}},
+		},
+	}))
+}
+
+func TestRemoveNSplit(t *testing.T) {
+	weight := 2
+	require.Equal(t, NSplitType{
+		Nodes: []SplitNode{
+			{Node: PaneType{}},
+			{Node: PaneType{Attached: true}, Weight: &weight},
+		},
+	}, RemoveAttached(NSplitType{
+		Nodes: []SplitNode{
+			{Node: PaneType{}},
+			{Node: PaneType{Attached: true}},
+			{Node: PaneType{}, Weight: &weight},
+		},
+	}))
+
+	// The last node attaches to the one before it
+	require.Equal(t, NSplitType{
+		Nodes: []SplitNode{
+			{Node: PaneType{}},
+			{Node: PaneType{Attached: true}},
+		},
+	}, RemoveAttached(NSplitType{
+		Nodes: []SplitNode{
+			{Node: PaneType{}},
+			{Node: PaneType{}},
+			{Node: PaneType{Attached: true}},
+		},
+	}))
+
+	// Only one node remains
+	require.Equal(t, PaneType{Attached: true}, RemoveAttached(NSplitType{
+		Nodes: []SplitNode{
+			{Node: PaneType{Attached: true}},
+			{Node: PaneType{}},
+		},
+	}))
+}
+
+func TestRemoveGrid(t *testing.T) {
+	require.Equal(t, GridType{
+		Nodes: []NodeType{
+			PaneType{},
+			PaneType{Attached: true},
+		},
+	}, RemoveAttached(GridType{
+		Nodes: []NodeType{
+			PaneType{},
+			PaneType{Attached: true},
+			PaneType{},
+		},
+	}))
+
+	require.Equal(t, SplitType{
+		A: PaneType{Attached: true},
+		B: PaneType{},
+	}, RemoveAttached(GridType{
+		Nodes: []NodeType{
+			SplitType{
+				A: PaneType{},
+				B: PaneType{},
+			},
+			PaneType{Attached: true},
+		},
+	}))
+}
+
+func TestHistory(t *testing.T) {
+	var (
+		a = New(PaneType{Attached: true})
+		b = New(MarginsType{Node: PaneType{Attached: true}})
+		c = New(BarType{Node: PaneType{Attached: true}})
+	)
+
+	var history History
+	_, ok := history.Undo(a)
+	require.False(t, ok)
+
+	history.Record(a, 10)
+	history.Record(b, 10)
+
+	layout, ok := history.Undo(c)
+	require.True(t, ok)
+	require.Equal(t, b, layout)
+
+	layout, ok = history.Redo(b)
+	require.True(t, ok)
+	require.Equal(t, c, layout)
+
+	_, ok = history.Redo(c)
+	require.False(t, ok)
+
+	// Recording a change clears what can be redone
+	history.Undo(c)
+	history.Record(b, 10)
+	_, ok = history.Redo(b)
+	require.False(t, ok)
+
+	// Only the most recent changes are kept
+	history.Record(c, 2)
+	layout, _ = history.Undo(a)
+	require.Equal(t, c, layout)
+	layout, _ = history.Undo(c)
+	require.Equal(t, b, layout)
+	_, ok = history.Undo(b)
+	require.False(t, ok)
+}
+
+func TestIsEquivalent(t *testing.T) {
+	var (
+		idA = tree.NodeID(1)
+		idB = tree.NodeID(2)
+	)
+
+	tabs := func(name string, active int) Layout {
+		return New(TabsType{
+			Tabs: []Tab{
+				{
+					Name:   name,
+					Active: active == 0,
+					Node: PaneType{
+						ID:       &idA,
+						Attached: active == 0,
+					},
+				},
+				{
+					Name:   "b",
+					Active: active == 1,
+					Node: PaneType{
+						ID:       &idB,
+						Attached: active == 1,
+					},
+				},
+			},
+		})
+	}
+
+	// Switching tabs is not a change
+	require.True(t, IsEquivalent(tabs("a", 0), tabs("a", 1), false))
+	// Renaming tabs is only a change if cosmetic changes count
+	require.False(t, IsEquivalent(tabs("a", 0), tabs("c", 0), false))
+	require.True(t, IsEquivalent(tabs("a", 0), tabs("c", 0), true))
+
+	borders := func(title string) Layout {
+		return New(BorderType{
+			Title: prop.NewStatic(title),
+			Node:  PaneType{ID: &idA, Attached: true},
+		})
+	}
+	require.True(t, IsEquivalent(borders("a"), borders("a"), false))
+	require.False(t, IsEquivalent(borders("a"), borders("b"), false))
+	require.True(t, IsEquivalent(borders("a"), borders("b"), true))
+
+	require.False(t, IsEquivalent(
+		New(PaneType{ID: &idA, Attached: true}),
+		New(PaneType{ID: &idB, Attached: true}),
+		true,
+	))
+}
+
+func TestUnzoom(t *testing.T) {
+	layout := TabsType{
+		Tabs: []Tab{
+			{Name: "first", Node: PaneType{}},
+			{
+				Name:   "second",
+				Active: true,
+				Node: MarginsType{
+					Cols: 80,
+					Node: SplitType{
+						A: PaneType{},
+						B: PaneType{Attached: true},
+					},
+				},
+			},
+		},
+	}
+
+	// Without decorations only the pane is replaced
+	id := tree.NodeID(1)
+	require.Equal(t, TabsType{
+		Tabs: []Tab{
+			{Name: "first", Node: PaneType{}},
+			{
+				Name:   "second",
+				Active: true,
+				Node: MarginsType{
+					Cols: 80,
+					Node: SplitType{
+						A: PaneType{},
+						B: PaneType{Attached: true, ID: &id},
+					},
+				},
+			},
+		},
+	}, Unzoom(
+		Copy(layout),
+		PaneType{Attached: true, ID: &id},
+		false,
+	))
+
+	// With decorations, changes to them are kept too
+	zoomed := Zoom(layout, true).(MarginsType)
+	zoomed.Cols = 100
+	unzoomed := Unzoom(Copy(layout), zoomed, true).(TabsType)
+	require.Equal(t, 100, unzoomed.Tabs[1].Node.(MarginsType).Cols)
+	require.Equal(
+		t,
+		layout.Tabs[1].Node.(MarginsType).Node,
+		unzoomed.Tabs[1].Node.(MarginsType).Node,
+	)
+}
@@ -41,6 +41,7 @@ type Margins struct {
 
 var _ mux.Screen = (*Margins)(nil)
 var _ L.Reusable = (*Margins)(nil)
+var _ L.Container = (*Margins)(nil)
 
 func fitMargin(outer, margin int) int {
 	if margin == 0 {
@@ -100,6 +101,12 @@ func (l *Margins) Apply(node L.NodeType) (bool, error) {
 	return true, l.recalculate()
 }
 
+func (l *Margins) Regions() []geom.Rect {
+	l.RLock()
+	defer l.RUnlock()
+	return []geom.Rect{l.inner}
+}
+
 func (l *Margins) Kill() {
 	l.RLock()
 	screen := l.screen
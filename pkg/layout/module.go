@@ -3,6 +3,7 @@ package layout
 import (
 	"fmt"
 
+	"github.com/cfoust/cy/pkg/geom"
 	"github.com/cfoust/cy/pkg/layout/prop"
 	"github.com/cfoust/cy/pkg/mux/screen/tree"
 
@@ -58,7 +59,10 @@ type TabsType struct {
 	InactiveFg, InactiveBg *prop.Color
 	Bg                     *prop.Color
 	Bottom                 bool
-	Tabs                   []Tab
+	// Whether to mark inactive tabs whose panes have written output or
+	// rung the bell since they were last shown.
+	Activity bool
+	Tabs     []Tab
 }
 
 // Active returns the Tab config of the currently active tab.
@@ -91,6 +95,90 @@ type BarType struct {
 	Node   NodeType
 }
 
+// Dimension is a length along one axis, expressed either as a number of cells
+// or as a percentage of the space available.
+type Dimension struct {
+	Value     int
+	IsPercent bool
+}
+
+// Cells returns the number of cells the Dimension occupies given the total
+// number of cells available.
+func (d Dimension) Cells(total int) int {
+	if !d.IsPercent {
+		return d.Value
+	}
+
+	return (d.Value * total) / 100
+}
+
+// Float is a node that is shown on top of the base node of a FloatType.
+type Float struct {
+	// The position of the top-left corner of the float. If nil, the float
+	// is centered along that axis.
+	Row, Col *Dimension
+	// The size of the float, including its border.
+	Width, Height *Dimension
+	Border        *prop.Border
+	BorderFg      *prop.Color
+	BorderBg      *prop.Color
+	Node          NodeType
+}
+
+// FloatType shows any number of floating nodes on top of a base node without
+// changing the base node's size. Floats later in the list are drawn on top of
+// the ones before them.
+type FloatType struct {
+	Node   NodeType
+	Floats []Float
+}
+
+// SplitNode is a single child of an NSplitType.
+type SplitNode struct {
+	// The share of the space along the split axis this node receives
+	// relative to its siblings. Defaults to 1.
+	Weight *int
+	// The minimum number of cells along the split axis this node is
+	// given, space permitting.
+	Min  *int
+	Node NodeType
+}
+
+// NSplitType divides its space along an axis between any number of nodes
+// according to their weights.
+type NSplitType struct {
+	Vertical bool
+	Border   *prop.Border
+	BorderFg *prop.Color
+	BorderBg *prop.Color
+	Nodes    []SplitNode
+}
+
+// GridType arranges its nodes into rows of Cols nodes each. Every row gets
+// the same amount of space, as does every node in a row.
+type GridType struct {
+	// The number of nodes in each row. If nil, the grid is as close to
+	// square as possible.
+	Cols     *int
+	Border   *prop.Border
+	BorderFg *prop.Color
+	BorderBg *prop.Color
+	Nodes    []NodeType
+}
+
+// GetCols returns the number of columns the grid will have.
+func (g GridType) GetCols() int {
+	if g.Cols != nil {
+		return geom.Max(*g.Cols, 1)
+	}
+
+	cols := 1
+	for cols*cols < len(g.Nodes) {
+		cols++
+	}
+	return cols
+}
+
 type Layout struct {
 	Root NodeType
 }
@@ -128,6 +216,37 @@ func getPaneType(tree NodeType) (panes []PaneType) {
 		for _, tab := range node.Tabs {
 			panes = append(panes, getPaneType(tab.Node)...)
 		}
+	case FloatType:
+		panes = append(panes, getPaneType(node.Node)...)
+		for _, float := range node.Floats {
+			panes = append(panes, getPaneType(float.Node)...)
+		}
+	case NSplitType:
+		for _, child := range node.Nodes {
+			panes = append(panes, getPaneType(child.Node)...)
+		}
+	case GridType:
+		for _, child := range node.Nodes {
+			panes = append(panes, getPaneType(child)...)
+		}
+	}
+	return
+}
+
+// NumPanes returns the number of panes in the node, including those in
+// inactive tabs.
+func NumPanes(node NodeType) int {
+	return getNumLeaves(node)
+}
+
+// PaneIDs returns the IDs of all of the panes in the node that have one, in
+// the order they appear in the node.
+func PaneIDs(node NodeType) (ids []tree.NodeID) {
+	for _, pane := range getPaneType(node) {
+		if pane.ID == nil {
+			continue
+		}
+		ids = append(ids, *pane.ID)
 	}
 	return
 }
@@ -151,6 +270,24 @@ func getNumLeaves(node NodeType) int {
 			result += getNumLeaves(tab.Node)
 		}
 		return result
+	case FloatType:
+		result := getNumLeaves(node.Node)
+		for _, float := range node.Floats {
+			result += getNumLeaves(float.Node)
+		}
+		return result
+	case NSplitType:
+		var result int
+		for _, child := range node.Nodes {
+			result += getNumLeaves(child.Node)
+		}
+		return result
+	case GridType:
+		var result int
+		for _, child := range node.Nodes {
+			result += getNumLeaves(child)
+		}
+		return result
 	}
 	return 0
 }
@@ -189,6 +326,35 @@ func Copy(node NodeType) NodeType {
 		}
 		copied.Tabs = newTabs
 		return copied
+	case FloatType:
+		copied := node
+		copied.Node = Copy(node.Node)
+		var newFloats []Float
+		for _, float := range node.Floats {
+			copiedFloat := float
+			copiedFloat.Node = Copy(float.Node)
+			newFloats = append(newFloats, copiedFloat)
+		}
+		copied.Floats = newFloats
+		return copied
+	case NSplitType:
+		copied := node
+		var newNodes []SplitNode
+		for _, child := range node.Nodes {
+			copiedChild := child
+			copiedChild.Node = Copy(child.Node)
+			newNodes = append(newNodes, copiedChild)
+		}
+		copied.Nodes = newNodes
+		return copied
+	case GridType:
+		copied := node
+		var newNodes []NodeType
+		for _, child := range node.Nodes {
+			newNodes = append(newNodes, Copy(child))
+		}
+		copied.Nodes = newNodes
+		return copied
 	}
 
 	return node
@@ -220,6 +386,19 @@ func AttachFirst(node NodeType) NodeType {
 			node.Tabs[i].Node = AttachFirst(tab.Node)
 		}
 		return node
+	case FloatType:
+		node.Node = AttachFirst(node.Node)
+		return node
+	case NSplitType:
+		if len(node.Nodes) > 0 {
+			node.Nodes[0].Node = AttachFirst(node.Nodes[0].Node)
+		}
+		return node
+	case GridType:
+		if len(node.Nodes) > 0 {
+			node.Nodes[0] = AttachFirst(node.Nodes[0])
+		}
+		return node
 	}
 
 	return node
@@ -283,6 +462,95 @@ func RemoveAttached(node NodeType) NodeType {
 		newNode := node
 		newNode.Tabs = newTabs
 		return newNode
+	case FloatType:
+		floats := append([]Float{}, node.Floats...)
+
+		if IsAttached(node.Node) {
+			if getNumLeaves(node.Node) > 1 {
+				node.Node = RemoveAttached(node.Node)
+				return node
+			}
+
+			// The topmost float takes the place of the base node
+			if len(floats) == 0 {
+				return node.Node
+			}
+
+			top := floats[len(floats)-1]
+			node.Node = AttachFirst(top.Node)
+			node.Floats = floats[:len(floats)-1]
+			return node
+		}
+
+		for i, float := range floats {
+			if !IsAttached(float.Node) {
+				continue
+			}
+
+			if getNumLeaves(float.Node) > 1 {
+				floats[i].Node = RemoveAttached(float.Node)
+				node.Floats = floats
+				return node
+			}
+
+			// Dismiss the float and go back to the base node
+			node.Floats = append(floats[:i], floats[i+1:]...)
+			node.Node = AttachFirst(node.Node)
+			return node
+		}
+
+		return node
+	case NSplitType:
+		nodes := append([]SplitNode{}, node.Nodes...)
+		for i, child := range nodes {
+			if !IsAttached(child.Node) {
+				continue
+			}
+
+			if getNumLeaves(child.Node) > 1 {
+				nodes[i].Node = RemoveAttached(child.Node)
+				node.Nodes = nodes
+				return node
+			}
+
+			nodes = append(nodes[:i], nodes[i+1:]...)
+			if len(nodes) == 1 {
+				return AttachFirst(nodes[0].Node)
+			}
+
+			// Attach to the node that took its place
+			next := geom.Min(i, len(nodes)-1)
+			nodes[next].Node = AttachFirst(nodes[next].Node)
+			node.Nodes = nodes
+			return node
+		}
+
+		return node
+	case GridType:
+		nodes := append([]NodeType{}, node.Nodes...)
+		for i, child := range nodes {
+			if !IsAttached(child) {
+				continue
+			}
+
+			if getNumLeaves(child) > 1 {
+				nodes[i] = RemoveAttached(child)
+				node.Nodes = nodes
+				return node
+			}
+
+			nodes = append(nodes[:i], nodes[i+1:]...)
+			if len(nodes) == 1 {
+				return AttachFirst(nodes[0])
+			}
+
+			next := geom.Min(i, len(nodes)-1)
+			nodes[next] = AttachFirst(nodes[next])
+			node.Nodes = nodes
+			return node
+		}
+
+		return node
 	}
 
 	return node
@@ -309,6 +577,31 @@ func IsAttached(tree NodeType) bool {
 			}
 		}
 		return false
+	case FloatType:
+		if IsAttached(node.Node) {
+			return true
+		}
+
+		for _, float := range node.Floats {
+			if IsAttached(float.Node) {
+				return true
+			}
+		}
+		return false
+	case NSplitType:
+		for _, child := range node.Nodes {
+			if IsAttached(child.Node) {
+				return true
+			}
+		}
+		return false
+	case GridType:
+		for _, child := range node.Nodes {
+			if IsAttached(child) {
+				return true
+			}
+		}
+		return false
 	}
 	return false
 }
@@ -337,6 +630,22 @@ func Detach(node NodeType) NodeType {
 			node.Tabs[i].Node = Detach(tab.Node)
 		}
 		return node
+	case FloatType:
+		node.Node = Detach(node.Node)
+		for i, float := range node.Floats {
+			node.Floats[i].Node = Detach(float.Node)
+		}
+		return node
+	case NSplitType:
+		for i, child := range node.Nodes {
+			node.Nodes[i].Node = Detach(child.Node)
+		}
+		return node
+	case GridType:
+		for i, child := range node.Nodes {
+			node.Nodes[i] = Detach(child)
+		}
+		return node
 	}
 
 	return node
@@ -373,6 +682,22 @@ func attach(node NodeType, id tree.NodeID) NodeType {
 			node.Tabs[i].Node = attach(tab.Node, id)
 		}
 		return node
+	case FloatType:
+		node.Node = attach(node.Node, id)
+		for i, float := range node.Floats {
+			node.Floats[i].Node = attach(float.Node, id)
+		}
+		return node
+	case NSplitType:
+		for i, child := range node.Nodes {
+			node.Nodes[i].Node = attach(child.Node, id)
+		}
+		return node
+	case GridType:
+		for i, child := range node.Nodes {
+			node.Nodes[i] = attach(child, id)
+		}
+		return node
 	}
 
 	return node
@@ -412,6 +737,31 @@ func getAttached(node NodeType) *tree.NodeID {
 			}
 		}
 		return nil
+	case FloatType:
+		if id := getAttached(node.Node); id != nil {
+			return id
+		}
+
+		for _, float := range node.Floats {
+			if id := getAttached(float.Node); id != nil {
+				return id
+			}
+		}
+		return nil
+	case NSplitType:
+		for _, child := range node.Nodes {
+			if id := getAttached(child.Node); id != nil {
+				return id
+			}
+		}
+		return nil
+	case GridType:
+		for _, child := range node.Nodes {
+			if id := getAttached(child); id != nil {
+				return id
+			}
+		}
+		return nil
 	}
 
 	return nil
@@ -422,6 +772,190 @@ func Attached(layout Layout) *tree.NodeID {
 	return getAttached(layout.Root)
 }
 
+// AttachedIndex returns the index of the attached pane among all of the panes
+// in the tree (or -1 if no pane is attached) along with the number of panes.
+func AttachedIndex(node NodeType) (index, numPanes int) {
+	panes := getPaneType(node)
+	for i, pane := range panes {
+		if pane.Attached {
+			return i, len(panes)
+		}
+	}
+
+	return -1, len(panes)
+}
+
+// Zoom returns a tree that contains only the attached pane. If
+// keepDecorations is true, the :margins, :borders, and :bar nodes that
+// enclose the attached pane are preserved. Zoom returns nil if no pane is
+// attached.
+func Zoom(node NodeType, keepDecorations bool) NodeType {
+	if !IsAttached(node) {
+		return nil
+	}
+
+	switch node := node.(type) {
+	case PaneType:
+		return node
+	case SplitType:
+		if IsAttached(node.A) {
+			return Zoom(node.A, keepDecorations)
+		}
+		return Zoom(node.B, keepDecorations)
+	case MarginsType:
+		inner := Zoom(node.Node, keepDecorations)
+		if !keepDecorations {
+			return inner
+		}
+		node.Node = inner
+		return node
+	case BorderType:
+		inner := Zoom(node.Node, keepDecorations)
+		if !keepDecorations {
+			return inner
+		}
+		node.Node = inner
+		return node
+	case BarType:
+		inner := Zoom(node.Node, keepDecorations)
+		if !keepDecorations {
+			return inner
+		}
+		node.Node = inner
+		return node
+	case TabsType:
+		for _, tab := range node.Tabs {
+			if IsAttached(tab.Node) {
+				return Zoom(tab.Node, keepDecorations)
+			}
+		}
+	case FloatType:
+		if IsAttached(node.Node) {
+			return Zoom(node.Node, keepDecorations)
+		}
+
+		for _, float := range node.Floats {
+			if IsAttached(float.Node) {
+				return Zoom(float.Node, keepDecorations)
+			}
+		}
+	case NSplitType:
+		for _, child := range node.Nodes {
+			if IsAttached(child.Node) {
+				return Zoom(child.Node, keepDecorations)
+			}
+		}
+	case GridType:
+		for _, child := range node.Nodes {
+			if IsAttached(child) {
+				return Zoom(child, keepDecorations)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Unzoom is the inverse of Zoom. It returns `node` with the part of it that
+// Zoom(node, keepDecorations) would return replaced by `zoomed`, which lets
+// changes made to a zoomed layout be applied to the full layout. Like
+// Detach, it modifies `node`, so callers should pass a copy.
+func Unzoom(node, zoomed NodeType, keepDecorations bool) NodeType {
+	if !IsAttached(node) {
+		return node
+	}
+
+	switch node := node.(type) {
+	case PaneType:
+		return zoomed
+	case SplitType:
+		if IsAttached(node.A) {
+			node.A = Unzoom(node.A, zoomed, keepDecorations)
+			return node
+		}
+		node.B = Unzoom(node.B, zoomed, keepDecorations)
+		return node
+	case MarginsType:
+		if !keepDecorations {
+			node.Node = Unzoom(node.Node, zoomed, keepDecorations)
+			return node
+		}
+
+		// Zoom kept this node, so `zoomed` is its replacement
+		replaced, ok := zoomed.(MarginsType)
+		if !ok {
+			return zoomed
+		}
+		replaced.Node = Unzoom(node.Node, replaced.Node, keepDecorations)
+		return replaced
+	case BorderType:
+		if !keepDecorations {
+			node.Node = Unzoom(node.Node, zoomed, keepDecorations)
+			return node
+		}
+
+		// Zoom kept this node, so `zoomed` is its replacement
+		replaced, ok := zoomed.(BorderType)
+		if !ok {
+			return zoomed
+		}
+		replaced.Node = Unzoom(node.Node, replaced.Node, keepDecorations)
+		return replaced
+	case BarType:
+		if !keepDecorations {
+			node.Node = Unzoom(node.Node, zoomed, keepDecorations)
+			return node
+		}
+
+		// Zoom kept this node, so `zoomed` is its replacement
+		replaced, ok := zoomed.(BarType)
+		if !ok {
+			return zoomed
+		}
+		replaced.Node = Unzoom(node.Node, replaced.Node, keepDecorations)
+		return replaced
+	case TabsType:
+		for i, tab := range node.Tabs {
+			if IsAttached(tab.Node) {
+				node.Tabs[i].Node = Unzoom(tab.Node, zoomed, keepDecorations)
+				break
+			}
+		}
+		return node
+	case FloatType:
+		if IsAttached(node.Node) {
+			node.Node = Unzoom(node.Node, zoomed, keepDecorations)
+			return node
+		}
+
+		for i, float := range node.Floats {
+			if IsAttached(float.Node) {
+				node.Floats[i].Node = Unzoom(float.Node, zoomed, keepDecorations)
+				break
+			}
+		}
+		return node
+	case NSplitType:
+		for i, child := range node.Nodes {
+			if IsAttached(child.Node) {
+				node.Nodes[i].Node = Unzoom(child.Node, zoomed, keepDecorations)
+				break
+			}
+		}
+		return node
+	case GridType:
+		for i, child := range node.Nodes {
+			if IsAttached(child) {
+				node.Nodes[i] = Unzoom(child, zoomed, keepDecorations)
+				break
+			}
+		}
+		return node
+	}
+
+	return node
+}
+
 // validateNodes makes sure that nodes in the layout provided match a set of
 // constraints.
 func validateNodes(node NodeType) error {
@@ -479,6 +1013,93 @@ func validateNodes(node NodeType) error {
 			)
 		}
 
+		return nil
+	case FloatType:
+		if err := validateNodes(node.Node); err != nil {
+			return err
+		}
+
+		for index, float := range node.Floats {
+			for _, dimension := range []*Dimension{
+				float.Width,
+				float.Height,
+			} {
+				if dimension != nil && dimension.Value <= 0 {
+					return fmt.Errorf(
+						":floats index %d must have positive :width and :height",
+						index,
+					)
+				}
+			}
+
+			err := validateNodes(float.Node)
+			if err == nil {
+				continue
+			}
+
+			return fmt.Errorf(
+				":floats index %d is invalid: %s",
+				index,
+				err,
+			)
+		}
+
+		return nil
+	case NSplitType:
+		if len(node.Nodes) == 0 {
+			return fmt.Errorf(":nsplit must have at least one node")
+		}
+
+		for index, child := range node.Nodes {
+			if child.Weight != nil && *child.Weight <= 0 {
+				return fmt.Errorf(
+					":nodes index %d must have a positive :weight",
+					index,
+				)
+			}
+
+			if child.Min != nil && *child.Min < 0 {
+				return fmt.Errorf(
+					":nodes index %d must not have a negative :min",
+					index,
+				)
+			}
+
+			err := validateNodes(child.Node)
+			if err == nil {
+				continue
+			}
+
+			return fmt.Errorf(
+				":nodes index %d is invalid: %s",
+				index,
+				err,
+			)
+		}
+
+		return nil
+	case GridType:
+		if len(node.Nodes) == 0 {
+			return fmt.Errorf(":grid must have at least one node")
+		}
+
+		if node.Cols != nil && *node.Cols <= 0 {
+			return fmt.Errorf(":grid must have a positive :cols")
+		}
+
+		for index, child := range node.Nodes {
+			err := validateNodes(child)
+			if err == nil {
+				continue
+			}
+
+			return fmt.Errorf(
+				":nodes index %d is invalid: %s",
+				index,
+				err,
+			)
+		}
+
 		return nil
 	}
 
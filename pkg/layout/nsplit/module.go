@@ -0,0 +1,139 @@
+package nsplit
+
+import (
+	"context"
+
+	"github.com/cfoust/cy/pkg/geom"
+	L "github.com/cfoust/cy/pkg/layout"
+	"github.com/cfoust/cy/pkg/layout/prop"
+	"github.com/cfoust/cy/pkg/mux"
+)
+
+// NSplit divides its space along an axis between any number of Screens
+// according to their weights.
+type NSplit struct {
+	*Tiles
+}
+
+var _ mux.Screen = (*NSplit)(nil)
+var _ L.Reusable = (*NSplit)(nil)
+
+func (s *NSplit) Apply(node L.NodeType) (bool, error) {
+	config, ok := node.(L.NSplitType)
+	if !ok {
+		return false, nil
+	}
+
+	// Nodes cannot be added or removed without recreating the node
+	if len(config.Nodes) != s.NumScreens() {
+		return false, nil
+	}
+
+	var layouts []*L.Layout
+	for _, child := range config.Nodes {
+		layout := L.New(child.Node)
+		layouts = append(layouts, &layout)
+	}
+
+	for _, prop := range []prop.Presettable{
+		config.Border,
+		config.BorderFg,
+		config.BorderBg,
+	} {
+		prop.Preset(
+			s.Ctx(),
+			s.Context.Context(),
+			layouts,
+		)
+		prop.SetLogger(s.Logger)
+	}
+
+	return true, s.Configure(
+		config.Border,
+		config.BorderFg,
+		config.BorderBg,
+		arrangeSplit(config),
+	)
+}
+
+// arrangeSplit lays out the nodes of an NSplitType one after another along
+// the split axis.
+func arrangeSplit(config L.NSplitType) Arranger {
+	var weights, mins []int
+	for _, child := range config.Nodes {
+		weight := 1
+		if child.Weight != nil {
+			weight = *child.Weight
+		}
+
+		min := 0
+		if child.Min != nil {
+			min = *child.Min
+		}
+
+		weights = append(weights, weight)
+		mins = append(mins, min)
+	}
+
+	return func(size geom.Size, hasBorder bool) (
+		rects []geom.Rect,
+		lines []Line,
+	) {
+		total := size.C
+		if config.Vertical {
+			total = size.R
+		}
+
+		numBorders := 0
+		if hasBorder {
+			numBorders = len(weights) - 1
+		}
+
+		offset := 0
+		lengths := Distribute(total-numBorders, weights, mins)
+		for i, length := range lengths {
+			rect := geom.Rect{
+				Position: geom.Vec2{C: offset},
+				Size:     geom.Vec2{R: size.R, C: length},
+			}
+			if config.Vertical {
+				rect = geom.Rect{
+					Position: geom.Vec2{R: offset},
+					Size:     geom.Vec2{R: length, C: size.C},
+				}
+			}
+			rects = append(rects, rect)
+			offset += length
+
+			if !hasBorder || i == len(lengths)-1 {
+				continue
+			}
+
+			line := Line{
+				Rect: geom.Rect{
+					Position: geom.Vec2{C: offset},
+					Size:     geom.Vec2{R: size.R, C: 1},
+				},
+				IsColumn: true,
+			}
+			if config.Vertical {
+				line = Line{
+					Rect: geom.Rect{
+						Position: geom.Vec2{R: offset},
+						Size:     geom.Vec2{R: 1, C: size.C},
+					},
+				}
+			}
+			lines = append(lines, line)
+			offset++
+		}
+
+		return
+	}
+}
+
+func New(ctx context.Context, screens []mux.Screen) *NSplit {
+	return &NSplit{
+		Tiles: NewTiles(ctx, screens),
+	}
+}
@@ -0,0 +1,351 @@
+package nsplit
+
+import (
+	"context"
+
+	"github.com/cfoust/cy/pkg/emu"
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/geom/tty"
+	L "github.com/cfoust/cy/pkg/layout"
+	"github.com/cfoust/cy/pkg/layout/prop"
+	"github.com/cfoust/cy/pkg/mux"
+	"github.com/cfoust/cy/pkg/taro"
+
+	"github.com/sasha-s/go-deadlock"
+)
+
+// A Line is a straight line of cells that a border is drawn in.
+type Line struct {
+	geom.Rect
+	// Whether the line runs down a column rather than along a row.
+	IsColumn bool
+}
+
+// Arranger determines where each Screen in Tiles appears given the size of
+// the Tiles and whether borders should be drawn between Screens.
+type Arranger func(size geom.Size, hasBorder bool) ([]geom.Rect, []Line)
+
+// Tiles renders any number of Screens in non-overlapping rectangles with
+// borders drawn in the space between them. It is used to implement both the
+// :nsplit and :grid nodes.
+type Tiles struct {
+	*L.Computable
+	deadlock.RWMutex
+	*mux.UpdatePublisher
+
+	screens []mux.Screen
+
+	size  geom.Size
+	rects []geom.Rect
+	lines []Line
+
+	border             *prop.Border
+	borderFg, borderBg *prop.Color
+	arrange            Arranger
+}
+
+var _ mux.Screen = (*Tiles)(nil)
+var _ L.Container = (*Tiles)(nil)
+
+// NumScreens returns the number of Screens in the Tiles.
+func (t *Tiles) NumScreens() int {
+	t.RLock()
+	defer t.RUnlock()
+	return len(t.screens)
+}
+
+// Configure changes the borders drawn between Screens and how the Screens are
+// arranged.
+func (t *Tiles) Configure(
+	border *prop.Border,
+	borderFg, borderBg *prop.Color,
+	arrange Arranger,
+) error {
+	t.Lock()
+	defer t.Unlock()
+
+	t.border = border
+	t.borderFg = borderFg
+	t.borderBg = borderBg
+	t.arrange = arrange
+	return t.recalculate()
+}
+
+func (t *Tiles) Regions() []geom.Rect {
+	t.RLock()
+	defer t.RUnlock()
+	return t.rects
+}
+
+func (t *Tiles) Kill() {
+	t.RLock()
+	screens := t.screens
+	t.RUnlock()
+
+	for _, screen := range screens {
+		screen.Kill()
+	}
+}
+
+func (t *Tiles) hasBorder() bool {
+	value, ok := t.border.GetPreset()
+	return ok && !value.None()
+}
+
+func (t *Tiles) State() *tty.State {
+	t.RLock()
+	var (
+		size      = t.size
+		rects     = t.rects
+		lines     = t.lines
+		screens   = t.screens
+		hasBorder = t.hasBorder()
+	)
+	t.RUnlock()
+
+	state := tty.New(size)
+	state.CursorVisible = false
+
+	for i, screen := range screens {
+		if i >= len(rects) {
+			break
+		}
+
+		position := rects[i].Position
+		screenState := screen.State().Clone()
+		image.CopyRaw(position, state.Image, screenState.Image)
+
+		if !screenState.CursorVisible || state.CursorVisible {
+			continue
+		}
+
+		cursor := screenState.Cursor
+		cursor.R += position.R
+		cursor.C += position.C
+		state.Cursor = cursor
+		state.CursorVisible = true
+	}
+
+	if !hasBorder {
+		return state
+	}
+
+	borderStyle, _ := t.border.GetPreset()
+
+	fg := emu.DefaultFG
+	bg := emu.DefaultBG
+
+	if value, ok := t.borderFg.GetPreset(); ok {
+		fg = value.Emu()
+	}
+
+	if value, ok := t.borderBg.GetPreset(); ok {
+		bg = value.Emu()
+	}
+
+	for _, line := range lines {
+		char := []rune(borderStyle.Top)[0]
+		if line.IsColumn {
+			char = []rune(borderStyle.Left)[0]
+		}
+
+		for row := line.Position.R; row < line.Position.R+line.Size.R; row++ {
+			for col := line.Position.C; col < line.Position.C+line.Size.C; col++ {
+				if row < 0 || row >= size.R || col < 0 || col >= size.C {
+					continue
+				}
+
+				state.Image[row][col].Char = char
+				state.Image[row][col].FG = fg
+				state.Image[row][col].BG = bg
+			}
+		}
+	}
+
+	return state
+}
+
+// Send passes mouse events to the Screen beneath the mouse cursor and all
+// other events to every Screen.
+func (t *Tiles) Send(msg mux.Msg) {
+	t.RLock()
+	var (
+		rects   = t.rects
+		screens = t.screens
+	)
+	t.RUnlock()
+
+	mouseMsg, ok := msg.(taro.MouseMsg)
+	if !ok {
+		for _, screen := range screens {
+			screen.Send(msg)
+		}
+		return
+	}
+
+	for i, screen := range screens {
+		if i >= len(rects) || !rects[i].Contains(mouseMsg.Vec2) {
+			continue
+		}
+
+		screen.Send(taro.TranslateMouseMessage(
+			msg,
+			-rects[i].Position.C,
+			-rects[i].Position.R,
+		))
+		return
+	}
+}
+
+func (t *Tiles) poll(ctx context.Context, screen mux.Screen) {
+	updates := screen.Subscribe(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-updates.Recv():
+			if _, ok := event.(L.NodeChangeEvent); ok {
+				continue
+			}
+			t.Publish(event)
+		}
+	}
+}
+
+func (t *Tiles) recalculate() error {
+	size := t.size
+
+	// We don't want to recalculate until we have a real size
+	if size.IsZero() || t.arrange == nil {
+		return nil
+	}
+
+	rects, lines := t.arrange(size, t.hasBorder())
+	t.rects = rects
+	t.lines = lines
+
+	for i, screen := range t.screens {
+		if i >= len(rects) {
+			break
+		}
+
+		err := screen.Resize(rects[i].Size)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Tiles) Resize(size geom.Size) error {
+	t.Lock()
+	defer t.Unlock()
+	t.size = size
+	return t.recalculate()
+}
+
+// Distribute divides total cells between items in proportion to their
+// weights. Each item is given at least its minimum number of cells if there
+// is enough space for all of the minimums. Cells left over after rounding go
+// to the first items.
+func Distribute(total int, weights, mins []int) []int {
+	sizes := make([]int, len(weights))
+	if len(weights) == 0 || total <= 0 {
+		return sizes
+	}
+
+	getMin := func(i int) int {
+		if i >= len(mins) {
+			return 0
+		}
+		return mins[i]
+	}
+
+	var sumMins int
+	for i := range weights {
+		sumMins += getMin(i)
+	}
+
+	if sumMins > total {
+		mins = nil
+	}
+
+	// Items that would get less than their minimum are fixed at their
+	// minimum and the rest of the space is divided again among the others
+	isFixed := make([]bool, len(weights))
+	for {
+		remaining := total
+		var sumWeights int
+		for i, weight := range weights {
+			if isFixed[i] {
+				remaining -= getMin(i)
+				continue
+			}
+			sumWeights += weight
+		}
+
+		changed := false
+		for i, weight := range weights {
+			if isFixed[i] || sumWeights == 0 {
+				continue
+			}
+
+			if remaining*weight < getMin(i)*sumWeights {
+				isFixed[i] = true
+				changed = true
+			}
+		}
+
+		if changed {
+			continue
+		}
+
+		var (
+			allocated int
+			last      = -1
+		)
+		for i, weight := range weights {
+			if isFixed[i] {
+				sizes[i] = getMin(i)
+				continue
+			}
+
+			sizes[i] = remaining * weight / sumWeights
+			allocated += sizes[i]
+			last = i
+		}
+
+		if last == -1 {
+			return sizes
+		}
+
+		for i := 0; allocated < remaining; i = (i + 1) % len(weights) {
+			if isFixed[i] {
+				continue
+			}
+			sizes[i]++
+			allocated++
+		}
+
+		return sizes
+	}
+}
+
+// NewTiles creates a Tiles that renders the provided Screens. Nothing is
+// shown until Configure is called.
+func NewTiles(ctx context.Context, screens []mux.Screen) *Tiles {
+	tiles := &Tiles{
+		Computable:      L.NewComputable(ctx),
+		UpdatePublisher: mux.NewPublisher(),
+		screens:         screens,
+	}
+
+	for _, screen := range screens {
+		go tiles.poll(tiles.Ctx(), screen)
+	}
+
+	return tiles
+}
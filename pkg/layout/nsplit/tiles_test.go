@@ -0,0 +1,20 @@
+package nsplit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistribute(t *testing.T) {
+	require.Equal(t, []int{4, 3, 3}, Distribute(10, []int{1, 1, 1}, nil))
+	require.Equal(t, []int{4, 8}, Distribute(12, []int{1, 2}, nil))
+
+	// Minimums take space from the other items
+	require.Equal(t, []int{6, 2, 2}, Distribute(10, []int{1, 1, 1}, []int{6, 0, 0}))
+
+	// But are ignored if they cannot all be satisfied
+	require.Equal(t, []int{5, 5}, Distribute(10, []int{1, 1}, []int{8, 8}))
+
+	require.Equal(t, []int{0, 0}, Distribute(0, []int{1, 1}, nil))
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cfoust/cy/pkg/frames"
 	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
 	"github.com/cfoust/cy/pkg/geom/tty"
 	L "github.com/cfoust/cy/pkg/layout"
 	"github.com/cfoust/cy/pkg/mux"
@@ -37,10 +39,48 @@ type Pane struct {
 
 	isAttached   bool
 	removeOnExit bool
+
+	// The execution context of the layout, typically a client.
+	context interface{}
 }
 
 var _ mux.Screen = (*Pane)(nil)
 var _ L.Reusable = (*Pane)(nil)
+var _ L.Contextable = (*Pane)(nil)
+
+// framed is implemented by execution contexts that have a frame.
+type framed interface {
+	Frame() *frames.Framer
+}
+
+func (p *Pane) SetContext(context interface{}) {
+	p.Lock()
+	p.context = context
+	p.Unlock()
+}
+
+// getSizing determines how the screen of a pane in the tree should be sized
+// from its parameters.
+func getSizing(params *params.Parameters) server.Sizing {
+	switch params.PaneSizePolicy() {
+	case "largest":
+		return server.Sizing{Policy: server.SizePolicyLargest}
+	case "active":
+		return server.Sizing{Policy: server.SizePolicyActive}
+	case "fixed":
+		size := params.PaneFixedSize()
+		if len(size) != 2 || size[0] <= 0 || size[1] <= 0 {
+			break
+		}
+
+		return server.Sizing{
+			Policy: server.SizePolicyFixed,
+			Size:   geom.Vec2{R: size[0], C: size[1]},
+		}
+	}
+
+	return server.Sizing{Policy: server.SizePolicySmallest}
+}
 
 func (p *Pane) Send(msg mux.Msg) {
 	p.RLock()
@@ -142,8 +182,24 @@ func (p *Pane) attach(
 		), nil
 	}
 
+	options := []server.AttachOption{
+		server.WithSizing(func() server.Sizing {
+			return getSizing(pane.Params())
+		}),
+	}
+
+	if owner, ok := p.context.(framed); ok && owner.Frame() != nil {
+		framer := owner.Frame()
+		options = append(
+			options,
+			server.WithFill(func(i image.Image) {
+				framer.Frame()(i)
+			}),
+		)
+	}
+
 	client := p.server.AddClient(ctx, p.size)
-	client.Attach(ctx, pane.Screen())
+	client.Attach(ctx, pane.Screen(), options...)
 
 	// When the tree node is removed (ie by (tree/kill)) we need to tell
 	// the layout engine to remove the reference to that NodeID from the
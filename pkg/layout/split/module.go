@@ -26,6 +26,8 @@ type Split struct {
 	// The size of the Split's screen.
 	size geom.Size
 
+	// The size of screen A.
+	sizeA geom.Size
 	// The location of screen B on the screen.
 	positionB geom.Size
 
@@ -43,10 +45,14 @@ type Split struct {
 	// screen A. This is calculated using `percent`.
 	cells  int
 	config L.SplitType
+
+	// Whether the user is dragging the border with the mouse.
+	isDragging bool
 }
 
 var _ mux.Screen = (*Split)(nil)
 var _ L.Reusable = (*Split)(nil)
+var _ L.Container = (*Split)(nil)
 
 func (s *Split) Kill() {
 	s.RLock()
@@ -60,6 +66,15 @@ func (s *Split) Kill() {
 	screenB.Kill()
 }
 
+func (s *Split) Regions() []geom.Rect {
+	s.RLock()
+	defer s.RUnlock()
+	return []geom.Rect{
+		{Size: s.sizeA},
+		{Position: s.positionB, Size: s.size.Sub(s.positionB)},
+	}
+}
+
 func (s *Split) State() *tty.State {
 	s.Lock()
 	defer s.Unlock()
@@ -174,7 +189,89 @@ func (s *Split) Apply(node L.NodeType) (bool, error) {
 	return true, s.recalculate()
 }
 
+// getBorder returns the location of the border along the split axis and
+// whether the border is visible.
+func (s *Split) getBorder() (int, bool) {
+	value, ok := s.config.Border.GetPreset()
+	if !ok || value.None() {
+		return 0, false
+	}
+
+	if s.isVertical {
+		return geom.Clamp(s.positionB.R-1, 0, s.size.R-1), true
+	}
+
+	return geom.Clamp(s.positionB.C-1, 0, s.size.C-1), true
+}
+
+// handleDrag lets the user resize the split by dragging its border with the
+// mouse. It reports whether the event was consumed.
+func (s *Split) handleDrag(msg taro.MouseMsg) bool {
+	s.Lock()
+
+	border, ok := s.getBorder()
+	if !ok {
+		s.isDragging = false
+		s.Unlock()
+		return false
+	}
+
+	axisCells := s.size.C
+	position := msg.C
+	if s.isVertical {
+		axisCells = s.size.R
+		position = msg.R
+	}
+
+	if !s.isDragging {
+		isBorderPress := msg.Type == taro.MousePress &&
+			msg.Button == taro.MouseLeft &&
+			msg.Down &&
+			position == border &&
+			geom.Rect{Size: s.size}.Contains(msg.Vec2)
+		s.isDragging = isBorderPress
+		s.Unlock()
+		return isBorderPress
+	}
+
+	// Releasing any button ends the drag
+	if msg.Type == taro.MousePress && !msg.Down {
+		s.isDragging = false
+		s.Unlock()
+		return true
+	}
+
+	if msg.Type != taro.MouseMotion || !msg.Down || axisCells < 3 {
+		s.Unlock()
+		return true
+	}
+
+	// The border sits in the last cell before screen B
+	cells := geom.Clamp(position+1, 2, axisCells-1)
+	if cells-1 == border {
+		s.Unlock()
+		return true
+	}
+
+	config := L.Copy(s.config).(L.SplitType)
+	if config.Cells != nil {
+		config.Cells = &cells
+	} else {
+		// Round up so that the border ends up where the mouse is
+		percent := (cells*100 + axisCells - 1) / axisCells
+		config.Percent = &percent
+	}
+	s.Unlock()
+
+	s.Publish(L.NodeChangeEvent{Config: config})
+	return true
+}
+
 func (s *Split) Send(msg mux.Msg) {
+	if mouseMsg, ok := msg.(taro.MouseMsg); ok && s.handleDrag(mouseMsg) {
+		return
+	}
+
 	s.screenA.Send(msg)
 
 	s.RLock()
@@ -256,6 +353,7 @@ func (s *Split) recalculate() error {
 			C: size.C,
 		}
 	}
+	s.sizeA = sizeA
 	err := s.screenA.Resize(sizeA)
 	if err != nil {
 		return err
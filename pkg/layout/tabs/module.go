@@ -2,6 +2,7 @@ package tabs
 
 import (
 	"context"
+	"math"
 
 	"github.com/cfoust/cy/pkg/emu"
 	"github.com/cfoust/cy/pkg/geom"
@@ -29,10 +30,32 @@ type Tabs struct {
 	// The tab bar at the time of the last render. Saving this lets us use
 	// it for hit detection on clicks.
 	lastBar image.Image
+
+	// Records activity in the panes of inactive tabs. May be nil.
+	monitor *Monitor
+
+	// Whether the user is dragging a tab with the mouse, and the index of
+	// that tab.
+	isDragging bool
+	dragIndex  int
 }
 
+const (
+	// The WriteIDs of the indicators shown when there are tabs beyond
+	// either edge of the tab bar.
+	scrollLeft  = emu.WriteID(math.MaxUint32)
+	scrollRight = emu.WriteID(math.MaxUint32 - 1)
+)
+
 var _ mux.Screen = (*Tabs)(nil)
 var _ L.Reusable = (*Tabs)(nil)
+var _ L.Container = (*Tabs)(nil)
+
+func (t *Tabs) Regions() []geom.Rect {
+	t.RLock()
+	defer t.RUnlock()
+	return []geom.Rect{t.inner}
+}
 
 func (t *Tabs) Kill() {
 	t.screen.Kill()
@@ -68,7 +91,7 @@ func (t *Tabs) State() *tty.State {
 		Background(activeBg)
 
 	inactiveFg := lipgloss.Color("0")
-	if value, ok := config.InactiveBg.GetPreset(); ok {
+	if value, ok := config.InactiveFg.GetPreset(); ok {
 		inactiveFg = value.Color
 	}
 
@@ -91,18 +114,21 @@ func (t *Tabs) State() *tty.State {
 	for index, tab := range config.Tabs {
 		name := tab.Name
 		cols := lipgloss.Width(name)
+		marker := t.getMarker(config, tab)
 
 		// If the given name contains ANSI escape sequences, we don't
 		// use the provided fg/bg colors and just render the name
 		// directly.
 		if len(name) == cols {
 			if tab.Active {
-				name = active.Render(tab.Name)
+				name = active.Render(tab.Name + marker)
 			} else {
-				name = inactive.Render(tab.Name)
+				name = inactive.Render(tab.Name + marker)
 			}
-			cols = lipgloss.Width(name)
+		} else {
+			name += marker
 		}
+		cols = lipgloss.Width(name)
 
 		i := image.New(geom.Vec2{
 			R: 1,
@@ -151,6 +177,17 @@ func (t *Tabs) State() *tty.State {
 		geom.Max(0, barWidth-size.C),
 	)
 	renderedBar[0] = renderedBar[0][barOffset:]
+
+	// Show which sides have tabs that do not fit on the screen
+	if barOffset > 0 && size.C > 0 {
+		renderedBar[0][0].Char = '<'
+		renderedBar[0][0].Write = scrollLeft
+	}
+	if barWidth-barOffset > size.C && size.C > 0 {
+		renderedBar[0][size.C-1].Char = '>'
+		renderedBar[0][size.C-1].Write = scrollRight
+	}
+
 	image.Copy(bar.Position, state.Image, renderedBar)
 
 	// Save this for hit detection
@@ -200,56 +237,43 @@ func (t *Tabs) Apply(node L.NodeType) (bool, error) {
 	}
 
 	t.config = config
-	return true, nil
-}
 
-func (t *Tabs) Send(msg mux.Msg) {
-	t.RLock()
-	var (
-		lastBar = t.lastBar
-		inner   = t.inner
-		bar     = t.bar
-	)
-	t.RUnlock()
-
-	mouseMsg, ok := msg.(taro.MouseMsg)
-	if !ok {
-		t.screen.Send(msg)
-		return
+	if t.monitor == nil || !config.Activity {
+		return true, nil
 	}
 
-	if inner.Contains(mouseMsg.Vec2) {
-		t.screen.Send(taro.TranslateMouseMessage(
-			msg,
-			-inner.Position.C,
-			-inner.Position.R,
-		))
-		return
+	for _, tab := range config.Tabs {
+		ids := L.PaneIDs(tab.Node)
+		if tab.Active {
+			t.monitor.Show(ids...)
+		} else {
+			t.monitor.Hide(ids...)
+		}
 	}
 
-	// Must be a click
-	if mouseMsg.Type != taro.MousePress || mouseMsg.Button != taro.MouseLeft || mouseMsg.Down {
-		return
-	}
+	return true, nil
+}
 
-	// And must be inside the bounds of the bar
-	if !bar.Contains(mouseMsg.Vec2) || mouseMsg.C < 0 || mouseMsg.C >= lastBar.Size().C {
-		return
+// getMarker returns the text shown after the name of `tab` to indicate what
+// happened in it while it was not shown.
+func (t *Tabs) getMarker(config L.TabsType, tab L.Tab) string {
+	if !config.Activity || tab.Active || t.monitor == nil {
+		return ""
 	}
 
-	tabIndex := int(lastBar[0][mouseMsg.C].Write)
-	t.RLock()
-	var (
-		config = L.Copy(t.config).(L.TabsType)
-	)
-	t.RUnlock()
-
-	if tabIndex == config.ActiveIndex() {
-		return
+	switch t.monitor.Get(L.PaneIDs(tab.Node)...) {
+	case MarkerActivity:
+		return "#"
+	case MarkerBell:
+		return "!"
 	}
+	return ""
+}
 
+// activate returns a copy of `config` in which the tab at `index` is
+// active.
+func activate(config L.TabsType, index int) L.TabsType {
 	isAttached := L.IsAttached(config)
-
 	if isAttached {
 		if newConfig, ok := L.Detach(config).(L.TabsType); ok {
 			config = newConfig
@@ -257,13 +281,8 @@ func (t *Tabs) Send(msg mux.Msg) {
 	}
 
 	var newTabs []L.Tab
-	for index, tab := range config.Tabs {
-		// Do nothing if we're already on this tab
-		if index == tabIndex && tab.Active {
-			return
-		}
-
-		isActive := index == tabIndex
+	for i, tab := range config.Tabs {
+		isActive := i == index
 		node := tab.Node
 
 		// Don't attach to the node unless we were otherwise attached
@@ -280,7 +299,165 @@ func (t *Tabs) Send(msg mux.Msg) {
 	}
 
 	config.Tabs = newTabs
-	t.Publish(L.NodeChangeEvent{Config: config})
+	return config
+}
+
+// moveTab returns a copy of `config` in which the tab at `from` has been
+// moved to `to`.
+func moveTab(config L.TabsType, from, to int) L.TabsType {
+	tabs := append([]L.Tab{}, config.Tabs...)
+	tab := tabs[from]
+	tabs = append(tabs[:from], tabs[from+1:]...)
+	tabs = append(tabs[:to], append([]L.Tab{tab}, tabs[to:]...)...)
+	config.Tabs = tabs
+	return config
+}
+
+// closeTab returns a copy of `config` without the tab at `index`. If that
+// tab was active, the tab that took its place becomes active. The last tab
+// cannot be closed.
+func closeTab(config L.TabsType, index int) (L.TabsType, bool) {
+	if len(config.Tabs) < 2 {
+		return config, false
+	}
+
+	wasActive := config.Tabs[index].Active
+	isAttached := L.IsAttached(config.Tabs[index].Node)
+
+	tabs := append([]L.Tab{}, config.Tabs[:index]...)
+	tabs = append(tabs, config.Tabs[index+1:]...)
+	config.Tabs = tabs
+
+	if !wasActive {
+		return config, true
+	}
+
+	next := geom.Min(index, len(tabs)-1)
+	config.Tabs[next].Active = true
+	if isAttached {
+		config.Tabs[next].Node = L.AttachFirst(config.Tabs[next].Node)
+	}
+	return config, true
+}
+
+// handleBar handles mouse events in the tab bar. `msg` is relative to the
+// top-left corner of the bar.
+func (t *Tabs) handleBar(msg taro.MouseMsg, lastBar image.Image) {
+	col := msg.C
+	isTab := msg.R == 0 && col >= 0 && col < lastBar.Size().C
+
+	t.Lock()
+	var (
+		config     = L.Copy(t.config).(L.TabsType)
+		isDragging = t.isDragging
+		dragIndex  = t.dragIndex
+	)
+
+	// Releasing any button ends the drag
+	if msg.Type == taro.MousePress && !msg.Down {
+		t.isDragging = false
+	}
+	t.Unlock()
+
+	if !isTab {
+		return
+	}
+
+	id := lastBar[0][col].Write
+	index := int(id)
+	isScroll := id == scrollLeft || id == scrollRight
+	if !isScroll && index >= len(config.Tabs) {
+		return
+	}
+
+	switch {
+	// Pressing the left button on a tab starts dragging it
+	case msg.Type == taro.MousePress && msg.Button == taro.MouseLeft && msg.Down:
+		if isScroll {
+			return
+		}
+
+		t.Lock()
+		t.isDragging = true
+		t.dragIndex = index
+		t.Unlock()
+		return
+	case msg.Type == taro.MouseMotion && msg.Down:
+		if !isDragging || isScroll || index == dragIndex || dragIndex >= len(config.Tabs) {
+			return
+		}
+
+		t.Lock()
+		t.dragIndex = index
+		t.Unlock()
+
+		t.Publish(L.NodeChangeEvent{
+			Config: moveTab(config, dragIndex, index),
+		})
+		return
+	case msg.Type == taro.MousePress && msg.Button == taro.MouseMiddle && !msg.Down:
+		if isScroll {
+			return
+		}
+
+		if newConfig, ok := closeTab(config, index); ok {
+			t.Publish(L.NodeChangeEvent{Config: newConfig})
+		}
+		return
+	case msg.Type == taro.MousePress && msg.Button == taro.MouseLeft && !msg.Down:
+		active := config.ActiveIndex()
+		switch id {
+		case scrollLeft:
+			index = geom.Max(active-1, 0)
+		case scrollRight:
+			index = geom.Min(active+1, len(config.Tabs)-1)
+		}
+
+		// Do nothing if we're already on this tab
+		if index == active {
+			return
+		}
+
+		t.Publish(L.NodeChangeEvent{Config: activate(config, index)})
+	}
+}
+
+func (t *Tabs) Send(msg mux.Msg) {
+	t.RLock()
+	var (
+		lastBar    = t.lastBar
+		inner      = t.inner
+		bar        = t.bar
+		isDragging = t.isDragging
+	)
+	t.RUnlock()
+
+	mouseMsg, ok := msg.(taro.MouseMsg)
+	if !ok {
+		t.screen.Send(msg)
+		return
+	}
+
+	// Dragging continues even if the mouse leaves the bar
+	if bar.Contains(mouseMsg.Vec2) || isDragging {
+		t.handleBar(
+			taro.TranslateMouseMessage(
+				mouseMsg,
+				-bar.Position.C,
+				-bar.Position.R,
+			).(taro.MouseMsg),
+			lastBar,
+		)
+		return
+	}
+
+	if inner.Contains(mouseMsg.Vec2) {
+		t.screen.Send(taro.TranslateMouseMessage(
+			msg,
+			-inner.Position.C,
+			-inner.Position.R,
+		))
+	}
 }
 
 func (t *Tabs) Resize(size geom.Size) error {
@@ -315,10 +492,24 @@ func (t *Tabs) Resize(size geom.Size) error {
 func (t *Tabs) poll(ctx context.Context) {
 	updates := t.screen.Subscribe(ctx)
 
+	// Markers changing means the tab bar needs to be rendered again
+	var markers <-chan mux.Msg
+	if t.monitor != nil {
+		markers = t.monitor.Subscribe(ctx).Recv()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-markers:
+			t.RLock()
+			isMonitoring := t.config.Activity
+			t.RUnlock()
+
+			if isMonitoring {
+				t.Notify()
+			}
 		case event := <-updates.Recv():
 			if _, ok := event.(L.NodeChangeEvent); ok {
 				continue
@@ -328,7 +519,13 @@ func (t *Tabs) poll(ctx context.Context) {
 	}
 }
 
-func New(ctx context.Context, screen mux.Screen) *Tabs {
+// New creates a Tabs that shows `screen` beneath its tab bar. `monitor`,
+// which may be nil, is used to mark tabs with activity.
+func New(
+	ctx context.Context,
+	screen mux.Screen,
+	monitor *Monitor,
+) *Tabs {
 	c := L.NewComputable(ctx)
 	tabs := &Tabs{
 		Computable:      c,
@@ -336,6 +533,7 @@ func New(ctx context.Context, screen mux.Screen) *Tabs {
 		screen:          screen,
 		size:            geom.DEFAULT_SIZE,
 		render:          taro.NewRenderer(),
+		monitor:         monitor,
 	}
 
 	go tabs.poll(tabs.Ctx())
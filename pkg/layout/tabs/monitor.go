@@ -0,0 +1,171 @@
+package tabs
+
+import (
+	"context"
+
+	"github.com/cfoust/cy/pkg/mux"
+	S "github.com/cfoust/cy/pkg/mux/screen"
+	"github.com/cfoust/cy/pkg/mux/screen/tree"
+	"github.com/cfoust/cy/pkg/util"
+
+	"github.com/sasha-s/go-deadlock"
+)
+
+// Marker describes what happened in a tab while it was not shown.
+type Marker int
+
+const (
+	MarkerNone Marker = iota
+	// A pane in the tab wrote output.
+	MarkerActivity
+	// A pane in the tab rang the bell.
+	MarkerBell
+)
+
+// Monitor records which panes wrote output or rang the bell while they were
+// hidden in an inactive tab. A single Monitor is shared by every Tabs in a
+// layout so that markers are not lost when a Tabs is recreated.
+type Monitor struct {
+	util.Lifetime
+	deadlock.RWMutex
+	*mux.UpdatePublisher
+
+	tree *tree.Tree
+	// The panes that are hidden and what happened in them since they
+	// were hidden.
+	hidden map[tree.NodeID]Marker
+	// The panes whose updates are being watched and the functions that
+	// stop watching them.
+	watching map[tree.NodeID]*watcher
+}
+
+type watcher struct {
+	cancel context.CancelFunc
+}
+
+// Hide starts recording what happens in the panes with the provided IDs.
+// Panes that are already hidden keep their markers.
+func (m *Monitor) Hide(ids ...tree.NodeID) {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, id := range ids {
+		if _, ok := m.hidden[id]; !ok {
+			m.hidden[id] = MarkerNone
+		}
+
+		if _, ok := m.watching[id]; ok {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(m.Ctx())
+		w := &watcher{cancel: cancel}
+		m.watching[id] = w
+		go m.watch(ctx, id, w)
+	}
+}
+
+// Show clears the markers of the panes with the provided IDs and stops
+// recording what happens in them.
+func (m *Monitor) Show(ids ...tree.NodeID) {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, id := range ids {
+		delete(m.hidden, id)
+
+		if w, ok := m.watching[id]; ok {
+			w.cancel()
+			delete(m.watching, id)
+		}
+	}
+}
+
+// Get returns the most important marker among the panes with the provided
+// IDs.
+func (m *Monitor) Get(ids ...tree.NodeID) (marker Marker) {
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, id := range ids {
+		if value, ok := m.hidden[id]; ok && value > marker {
+			marker = value
+		}
+	}
+	return
+}
+
+func (m *Monitor) record(id tree.NodeID, marker Marker) {
+	m.Lock()
+	current, ok := m.hidden[id]
+	if !ok || current >= marker {
+		m.Unlock()
+		return
+	}
+	m.hidden[id] = marker
+	m.Unlock()
+
+	m.Notify()
+}
+
+// forget stops tracking a pane after `w` stops watching it. If the pane was
+// shown and hidden again in the meantime, it is being watched by a different
+// watcher and nothing is forgotten.
+func (m *Monitor) forget(id tree.NodeID, w *watcher) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.watching[id] != w {
+		return
+	}
+
+	delete(m.hidden, id)
+	delete(m.watching, id)
+}
+
+func (m *Monitor) watch(
+	ctx context.Context,
+	id tree.NodeID,
+	w *watcher,
+) {
+	defer w.cancel()
+	defer m.forget(id, w)
+
+	node, ok := m.tree.NodeById(id)
+	if !ok {
+		return
+	}
+
+	pane, ok := node.(*tree.Pane)
+	if !ok {
+		return
+	}
+
+	updates := pane.Screen().Subscribe(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-pane.Ctx().Done():
+			return
+		case event := <-updates.Recv():
+			switch event.(type) {
+			case nil:
+				m.record(id, MarkerActivity)
+			case S.BellEvent:
+				m.record(id, MarkerBell)
+			}
+		}
+	}
+}
+
+// NewMonitor creates a Monitor that watches panes in `t`.
+func NewMonitor(ctx context.Context, t *tree.Tree) *Monitor {
+	return &Monitor{
+		Lifetime:        util.NewLifetime(ctx),
+		UpdatePublisher: mux.NewPublisher(),
+		tree:            t,
+		hidden:          make(map[tree.NodeID]Marker),
+		watching:        make(map[tree.NodeID]*watcher),
+	}
+}
@@ -0,0 +1,65 @@
+package tabs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cfoust/cy/pkg/layout/pane"
+	S "github.com/cfoust/cy/pkg/mux/screen"
+	T "github.com/cfoust/cy/pkg/mux/screen/tree"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor(t *testing.T) {
+	ctx := context.Background()
+	tree := T.NewTree()
+	static := pane.NewStatic(ctx, false, "foo")
+	id := tree.Root().NewPane(ctx, static).Id()
+
+	m := NewMonitor(ctx, tree)
+
+	// Nothing is recorded for panes that are shown
+	static.Notify()
+	require.Equal(t, MarkerNone, m.Get(id))
+
+	m.Hide(id)
+	time.Sleep(100 * time.Millisecond)
+
+	static.Notify()
+	require.Eventually(t, func() bool {
+		return m.Get(id) == MarkerActivity
+	}, time.Second, 10*time.Millisecond)
+
+	static.Publish(S.BellEvent{})
+	require.Eventually(t, func() bool {
+		return m.Get(id) == MarkerBell
+	}, time.Second, 10*time.Millisecond)
+
+	// Output does not hide a bell
+	static.Notify()
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, MarkerBell, m.Get(id))
+
+	m.Show(id)
+	require.Equal(t, MarkerNone, m.Get(id))
+
+	// Panes that are shown are no longer watched
+	m.RLock()
+	require.Len(t, m.watching, 0)
+	m.RUnlock()
+
+	static.Notify()
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, MarkerNone, m.Get(id))
+
+	// Hiding the pane again starts a new watcher
+	m.Hide(id)
+	time.Sleep(100 * time.Millisecond)
+
+	static.Notify()
+	require.Eventually(t, func() bool {
+		return m.Get(id) == MarkerActivity
+	}, time.Second, 10*time.Millisecond)
+}
@@ -3,6 +3,7 @@ package layout
 import (
 	"context"
 
+	"github.com/cfoust/cy/pkg/geom"
 	"github.com/cfoust/cy/pkg/mux"
 	"github.com/cfoust/cy/pkg/util"
 
@@ -19,6 +20,15 @@ type Reusable interface {
 	Apply(NodeType) (bool, error)
 }
 
+// Container is implemented by Screens that contain other Screens. It is used
+// to find where each pane in a layout appears on the screen.
+type Container interface {
+	// Regions returns the area each child Screen occupies relative to
+	// the top-left corner of the Container, in the same order the
+	// children were provided.
+	Regions() []geom.Rect
+}
+
 // Loggable is used for passing a logger to the Screen when appropriate.
 type Loggable interface {
 	SetLogger(zerolog.Logger)
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
 	"github.com/cfoust/cy/pkg/geom/tty"
 	"github.com/cfoust/cy/pkg/mux"
 	"github.com/cfoust/cy/pkg/util"
@@ -19,6 +20,29 @@ type Client struct {
 	size       mux.Size
 	screen     mux.Screen
 	attachment *util.Lifetime
+
+	sizing func() Sizing
+	fill   func(image.Image)
+}
+
+// AttachOption configures how a Client shows a screen.
+type AttachOption func(*Client)
+
+// WithSizing sets the function used to determine how the screen should be
+// resized when it is shown on more than one client. The screen is sized
+// according to SizePolicySmallest if this is not provided.
+func WithSizing(sizing func() Sizing) AttachOption {
+	return func(c *Client) {
+		c.sizing = sizing
+	}
+}
+
+// WithFill sets the function used to draw the area of the client that the
+// screen does not cover because it is smaller than the client.
+func WithFill(fill func(image.Image)) AttachOption {
+	return func(c *Client) {
+		c.fill = fill
+	}
 }
 
 var _ mux.Screen = (*Client)(nil)
@@ -27,6 +51,7 @@ func (c *Client) State() *tty.State {
 	c.RLock()
 	screen := c.screen
 	size := c.size
+	fill := c.fill
 	defer c.RUnlock()
 
 	if screen == nil {
@@ -46,13 +71,24 @@ func (c *Client) State() *tty.State {
 			out,
 			state,
 		)
+
+		// When the screen is larger than the client, the cursor may
+		// not be visible
+		visible := geom.Rect{Size: size}
+		if !visible.Contains(out.Cursor.Vec2) {
+			out.CursorVisible = false
+		}
 		return out
 	}
 
-	for row := 0; row < size.R; row++ {
-		for col := 0; col < size.C; col++ {
-			out.Image[row][col].Char = '-'
-			out.Image[row][col].FG = 8
+	if fill != nil {
+		fill(out.Image)
+	} else {
+		for row := 0; row < size.R; row++ {
+			for col := 0; col < size.C; col++ {
+				out.Image[row][col].Char = '-'
+				out.Image[row][col].FG = 8
+			}
 		}
 	}
 
@@ -87,6 +123,7 @@ func (c *Client) Send(msg mux.Msg) {
 		return
 	}
 
+	c.server.setActive(c, screen)
 	screen.Send(msg)
 }
 
@@ -96,8 +133,11 @@ func (c *Client) Kill() {
 		c.attachment.Cancel()
 	}
 	c.attachment = nil
+	screen := c.screen
 	c.screen = nil
 	c.Unlock()
+
+	c.server.refreshPane(screen)
 }
 
 func (c *Client) Resize(size mux.Size) error {
@@ -128,7 +168,11 @@ func (c *Client) pollScreen(ctx context.Context, screen mux.Screen) error {
 	}
 }
 
-func (c *Client) Attach(ctx context.Context, screen mux.Screen) {
+func (c *Client) Attach(
+	ctx context.Context,
+	screen mux.Screen,
+	options ...AttachOption,
+) {
 	attachment := util.NewLifetime(ctx)
 
 	c.Lock()
@@ -136,6 +180,11 @@ func (c *Client) Attach(ctx context.Context, screen mux.Screen) {
 		c.attachment.Cancel()
 	}
 	c.attachment = &attachment
+	c.sizing = nil
+	c.fill = nil
+	for _, option := range options {
+		option(c)
+	}
 	oldScreen := c.screen
 	c.screen = screen
 	c.Unlock()
@@ -1,18 +1,106 @@
 package server
 
 import (
+	"slices"
+	"sync"
+
 	"github.com/cfoust/cy/pkg/geom"
 	"github.com/cfoust/cy/pkg/mux"
 
 	"github.com/sasha-s/go-deadlock"
 )
 
+// SizePolicy determines the size of a screen that is shown on more than one
+// client.
+type SizePolicy int
+
+const (
+	// The screen is the largest size that fits on every client.
+	SizePolicySmallest SizePolicy = iota
+	// The screen is large enough to fill the largest client.
+	SizePolicyLargest
+	// The screen is the size of the client that most recently sent it
+	// input.
+	SizePolicyActive
+	// The screen is always the same size.
+	SizePolicyFixed
+)
+
+// Sizing describes how a screen should be resized.
+type Sizing struct {
+	Policy SizePolicy
+	// The size of the screen when Policy is SizePolicyFixed.
+	Size geom.Vec2
+}
+
 type Server struct {
 	deadlock.RWMutex
 	clients []*Client
+
+	// The client that most recently sent input to each screen.
+	active map[mux.Screen]*Client
+
+	// The size each screen should be. Screens are resized in the
+	// background, so resizeLock ensures that only the latest size is
+	// applied. It is held while a screen resizes and other resizes are
+	// expected to wait for it, so it is not a deadlock.Mutex.
+	sizes      map[mux.Screen]geom.Vec2
+	resizeLock sync.Mutex
+}
+
+// getSizing gets the Sizing for a screen from the clients attached to it.
+func getSizing(attached []*Client) Sizing {
+	for _, client := range attached {
+		client.RLock()
+		sizing := client.sizing
+		client.RUnlock()
+
+		if sizing != nil {
+			return sizing()
+		}
+	}
+
+	return Sizing{}
+}
+
+// setActive records that `client` sent input to `screen`, resizing it if
+// the change matters.
+func (s *Server) setActive(client *Client, screen mux.Screen) {
+	s.RLock()
+	previous := s.active[screen]
+	s.RUnlock()
+	if previous == client {
+		return
+	}
+
+	s.Lock()
+	s.active[screen] = client
+	s.Unlock()
+
+	s.refreshPane(screen)
 }
 
-// refreshPane resizes screen to the minimum of all of its clients' screen sizes.
+// resize sets the size of `screen` to `size` in the background. The caller
+// must hold the lock on the Server.
+func (s *Server) resize(screen mux.Screen, size geom.Vec2) {
+	s.sizes[screen] = size
+
+	go func() {
+		s.resizeLock.Lock()
+		defer s.resizeLock.Unlock()
+
+		s.RLock()
+		size, ok := s.sizes[screen]
+		s.RUnlock()
+		if !ok {
+			return
+		}
+
+		screen.Resize(size)
+	}()
+}
+
+// refreshPane resizes screen according to the Sizing of its clients.
 func (s *Server) refreshPane(screen mux.Screen) {
 	if screen == nil {
 		return
@@ -31,10 +119,36 @@ func (s *Server) refreshPane(screen mux.Screen) {
 
 	// Don't do anything if no clients are attached to this pane
 	if len(attached) == 0 {
+		delete(s.active, screen)
+		delete(s.sizes, screen)
+		return
+	}
+
+	// Forget the active client if it is no longer attached
+	if active, ok := s.active[screen]; ok && !slices.Contains(attached, active) {
+		delete(s.active, screen)
+	}
+
+	sizing := getSizing(attached)
+	if sizing.Policy == SizePolicyFixed && !sizing.Size.IsZero() {
+		s.resize(screen, sizing.Size)
 		return
 	}
 
-	// Set the pane's size to the maximum that all clients can fit
+	if sizing.Policy == SizePolicyActive {
+		active := s.active[screen]
+		for _, client := range attached {
+			if client != active {
+				continue
+			}
+
+			if size := client.Size(); !size.IsZero() {
+				s.resize(screen, size)
+				return
+			}
+		}
+	}
+
 	size := geom.Vec2{}
 	for _, client := range attached {
 		clientSize := client.Size()
@@ -46,18 +160,31 @@ func (s *Server) refreshPane(screen mux.Screen) {
 
 		if size.IsZero() {
 			size = clientSize
-		} else {
-			size = geom.GetMaximum(size, clientSize)
+			continue
 		}
+
+		if sizing.Policy == SizePolicyLargest {
+			size = geom.Vec2{
+				R: geom.Max(size.R, clientSize.R),
+				C: geom.Max(size.C, clientSize.C),
+			}
+			continue
+		}
+
+		// Set the pane's size to the maximum that all clients can fit
+		size = geom.GetMaximum(size, clientSize)
 	}
 
 	if size.IsZero() {
 		return
 	}
 
-	go screen.Resize(size)
+	s.resize(screen, size)
 }
 
 func New() *Server {
-	return &Server{}
+	return &Server{
+		active: make(map[mux.Screen]*Client),
+		sizes:  make(map[mux.Screen]geom.Vec2),
+	}
 }
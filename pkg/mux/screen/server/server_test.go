@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/geom/tty"
+	"github.com/cfoust/cy/pkg/mux"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testScreen is a Screen that records the size it was given.
+type testScreen struct {
+	sync.Mutex
+	*mux.UpdatePublisher
+	size geom.Vec2
+}
+
+var _ mux.Screen = (*testScreen)(nil)
+
+func (t *testScreen) State() *tty.State {
+	t.Lock()
+	defer t.Unlock()
+	return tty.New(t.size)
+}
+
+func (t *testScreen) Resize(size geom.Vec2) error {
+	t.Lock()
+	t.size = size
+	t.Unlock()
+	return nil
+}
+
+func (t *testScreen) Size() geom.Vec2 {
+	t.Lock()
+	defer t.Unlock()
+	return t.size
+}
+
+func (t *testScreen) Send(msg mux.Msg) {}
+
+func (t *testScreen) Kill() {}
+
+func TestSizing(t *testing.T) {
+	var (
+		small = geom.Vec2{R: 10, C: 40}
+		large = geom.Vec2{R: 20, C: 20}
+		fixed = geom.Vec2{R: 5, C: 5}
+	)
+
+	for _, test := range []struct {
+		name   string
+		sizing Sizing
+		active bool
+		size   geom.Vec2
+	}{
+		{
+			name:   "smallest",
+			sizing: Sizing{Policy: SizePolicySmallest},
+			size:   geom.Vec2{R: 10, C: 20},
+		},
+		{
+			name:   "largest",
+			sizing: Sizing{Policy: SizePolicyLargest},
+			size:   geom.Vec2{R: 20, C: 40},
+		},
+		{
+			name:   "no active client",
+			sizing: Sizing{Policy: SizePolicyActive},
+			size:   geom.Vec2{R: 10, C: 20},
+		},
+		{
+			name:   "active",
+			sizing: Sizing{Policy: SizePolicyActive},
+			active: true,
+			size:   large,
+		},
+		{
+			name: "fixed",
+			sizing: Sizing{
+				Policy: SizePolicyFixed,
+				Size:   fixed,
+			},
+			size: fixed,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := New()
+			screen := &testScreen{UpdatePublisher: mux.NewPublisher()}
+			sizing := WithSizing(func() Sizing {
+				return test.sizing
+			})
+
+			a := s.AddClient(ctx, small)
+			a.Attach(ctx, screen, sizing)
+			b := s.AddClient(ctx, large)
+			b.Attach(ctx, screen, sizing)
+
+			if test.active {
+				b.Send(nil)
+			}
+
+			require.Eventually(t, func() bool {
+				return screen.Size() == test.size
+			}, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestFill(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	screen := &testScreen{UpdatePublisher: mux.NewPublisher()}
+	screen.Resize(geom.Vec2{R: 2, C: 2})
+
+	client := s.AddClient(ctx, geom.Vec2{})
+	client.Attach(ctx, screen, WithFill(func(i image.Image) {
+		for row := range i {
+			for col := range i[row] {
+				i[row][col].Char = 'x'
+			}
+		}
+	}))
+	client.Lock()
+	client.size = geom.Vec2{R: 4, C: 4}
+	client.Unlock()
+
+	state := client.State()
+	require.Equal(t, 'x', state.Image[0][0].Char)
+	require.NotEqual(t, 'x', state.Image[1][1].Char)
+}
+
+func TestActiveDetach(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	screen := &testScreen{UpdatePublisher: mux.NewPublisher()}
+	other := &testScreen{UpdatePublisher: mux.NewPublisher()}
+	sizing := WithSizing(func() Sizing {
+		return Sizing{Policy: SizePolicyActive}
+	})
+
+	a := s.AddClient(ctx, geom.Vec2{R: 10, C: 10})
+	a.Attach(ctx, screen, sizing)
+	b := s.AddClient(ctx, geom.Vec2{R: 20, C: 20})
+	b.Attach(ctx, screen, sizing)
+	b.Send(nil)
+
+	s.RLock()
+	require.Equal(t, b, s.active[screen])
+	s.RUnlock()
+
+	// The screen forgets b once it detaches
+	b.Attach(ctx, other, sizing)
+	s.RLock()
+	_, ok := s.active[screen]
+	s.RUnlock()
+	require.False(t, ok)
+}
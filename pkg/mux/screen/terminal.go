@@ -21,6 +21,10 @@ type ExitEvent struct {
 	Code    int
 }
 
+// BellEvent is published when the program running in a Terminal rings the
+// bell.
+type BellEvent struct{}
+
 type Terminal struct {
 	deadlock.RWMutex
 	*mux.UpdatePublisher
@@ -157,6 +161,10 @@ func (t *Terminal) Write(p []byte) (n int, err error) {
 	// Let any clients know that this pane changed
 	t.Notify()
 
+	if t.terminal.Changes().Bell() {
+		t.Publish(BellEvent{})
+	}
+
 	return n, err
 }
 
@@ -45,6 +45,7 @@ func (g *Group) addNode(node Node) {
 	g.Lock()
 	g.children = append(g.children, node)
 	g.Unlock()
+	g.tree.Publish(ChildrenEvent{Group: g.Id()})
 }
 
 func (g *Group) removeNode(node Node) {
@@ -59,6 +60,7 @@ func (g *Group) removeNode(node Node) {
 	}
 	g.children = newChildren
 	g.Unlock()
+	g.tree.Publish(ChildrenEvent{Group: g.Id()})
 }
 
 func (g *Group) Leaves() []Node {
@@ -75,6 +77,7 @@ func (g *Group) NewPaneCreator(ctx context.Context) (NodeID, func(screen mux.Scr
 	return p.Id(), func(screen mux.Screen) *Pane {
 		p.screen = screen
 		metadata.params = g.params.NewChild()
+		setParams(screen, metadata.params)
 		g.addNode(p)
 
 		go func() {
@@ -101,6 +104,7 @@ func (g *Group) NewPane(ctx context.Context, screen mux.Screen) *Pane {
 	metadata := g.tree.newMetadata(pane)
 	pane.metaData = metadata
 	metadata.params = g.params.NewChild()
+	setParams(screen, metadata.params)
 	g.addNode(pane)
 
 	go func() {
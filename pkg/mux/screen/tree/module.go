@@ -8,3 +8,8 @@ type NodeEvent struct {
 	Id    NodeID
 	Event events.Msg
 }
+
+// ChildrenEvent is published when a Node is added to or removed from a Group.
+type ChildrenEvent struct {
+	Group NodeID
+}
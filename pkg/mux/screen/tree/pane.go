@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/cfoust/cy/pkg/mux"
+	"github.com/cfoust/cy/pkg/params"
 	"github.com/cfoust/cy/pkg/util"
 )
 
@@ -15,6 +16,19 @@ type Pane struct {
 
 var _ Node = (*Pane)(nil)
 
+// ParamReader is implemented by Screens that read the parameters of the
+// Pane that contains them.
+type ParamReader interface {
+	SetParams(params *params.Parameters)
+}
+
+// setParams gives `screen` the parameters of its Pane, if it wants them.
+func setParams(screen mux.Screen, params *params.Parameters) {
+	if reader, ok := screen.(ParamReader); ok {
+		reader.SetParams(params)
+	}
+}
+
 func (p *Pane) Screen() mux.Screen {
 	return p.screen
 }
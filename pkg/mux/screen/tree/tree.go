@@ -33,6 +33,21 @@ func (t *Tree) newMetadata(node Node) *metaData {
 	return metadata
 }
 
+// ReserveID makes the next node created in the tree have the ID `id`. This is
+// only possible if `id` is greater than the ID of every node that has ever
+// been created, so ReserveID reports whether it succeeded.
+func (t *Tree) ReserveID(id NodeID) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	if id <= t.nextNodeID.Load() {
+		return false
+	}
+
+	t.nextNodeID.Store(id - 1)
+	return true
+}
+
 func (t *Tree) storeNode(node Node) {
 	t.Lock()
 	defer t.Unlock()
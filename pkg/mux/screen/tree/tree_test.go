@@ -3,6 +3,7 @@ package tree
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/cfoust/cy/pkg/geom"
 	"github.com/cfoust/cy/pkg/mux/screen"
@@ -62,3 +63,38 @@ func TestRemoveRoot(t *testing.T) {
 	tree := NewTree()
 	require.Error(t, tree.RemoveNode(tree.Root().Id()))
 }
+
+func TestChildrenEvent(t *testing.T) {
+	tree := NewTree()
+	g := tree.Root().NewGroup()
+	updates := tree.Subscribe(context.Background())
+
+	waitFor := func() {
+		for {
+			select {
+			case event := <-updates.Recv():
+				if event, ok := event.(ChildrenEvent); ok {
+					require.Equal(t, g.Id(), event.Group)
+					return
+				}
+			case <-time.After(time.Second):
+				require.Fail(t, "no ChildrenEvent was published")
+			}
+		}
+	}
+
+	pane := emptyPane(g)
+	waitFor()
+
+	tree.RemoveNode(pane.Id())
+	waitFor()
+}
+
+func TestReserveID(t *testing.T) {
+	tree := NewTree()
+	require.True(t, tree.ReserveID(5))
+	require.Equal(t, NodeID(5), tree.Root().NewGroup().Id())
+	require.Equal(t, NodeID(6), tree.Root().NewGroup().Id())
+	require.False(t, tree.ReserveID(6))
+	require.Equal(t, NodeID(7), tree.Root().NewGroup().Id())
+}
@@ -2,10 +2,13 @@ package stream
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/cfoust/cy/pkg/util"
@@ -13,6 +16,7 @@ import (
 
 	"github.com/creack/pty"
 	"github.com/sasha-s/go-deadlock"
+	"golang.org/x/sys/unix"
 )
 
 type CmdOptions struct {
@@ -47,9 +51,33 @@ type Cmd struct {
 	proc *os.Process
 	done chan error
 
+	// A process started by another cy server that this Cmd should take
+	// over instead of starting a new one.
+	adopted *adoptedProcess
+	// Whether the process has been handed to another cy server.
+	released bool
+	// Closed when the pseudo-terminal of an adopted process hangs up,
+	// which means that the process has exited.
+	hangup     chan struct{}
+	hangupOnce sync.Once
+
+	// Whether reading from the pseudo-terminal is paused (see Pause.)
+	paused bool
+	// Whether a call to Read is reading from the pseudo-terminal or has
+	// returned output that the caller has not finished handling, which
+	// it has once it calls Read again.
+	reading bool
+	// Signaled whenever paused, reading, or released change.
+	readState *sync.Cond
+
 	exitError error
 }
 
+type adoptedProcess struct {
+	ptmx *os.File
+	proc *os.Process
+}
+
 var _ Stream = (*Cmd)(nil)
 
 func (c *Cmd) Kill() {
@@ -59,12 +87,36 @@ func (c *Cmd) Kill() {
 func (c *Cmd) Resize(size Size) error {
 	c.Lock()
 	c.size = size
+	ptmx := c.ptmx
 	c.Unlock()
 
-	return pty.Setsize(c.ptmx, &pty.Winsize{
-		Rows: uint16(size.R),
-		Cols: uint16(size.C),
+	if ptmx == nil {
+		return nil
+	}
+
+	// pty.Setsize calls (*os.File).Fd, which would put the
+	// pseudo-terminal back into blocking mode
+	conn, err := ptmx.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var ioctlErr error
+	err = conn.Control(func(fd uintptr) {
+		ioctlErr = unix.IoctlSetWinsize(
+			int(fd),
+			unix.TIOCSWINSZ,
+			&unix.Winsize{
+				Row: uint16(size.R),
+				Col: uint16(size.C),
+			},
+		)
 	})
+	if err != nil {
+		return err
+	}
+
+	return ioctlErr
 }
 
 // Options returns the original arguments used to start the command.
@@ -83,6 +135,121 @@ func (c *Cmd) Path() (string, error) {
 	return dir.ForPid(proc.Pid)
 }
 
+// PTY returns the pseudo-terminal and process ID of the command's running
+// process so that it can be handed to another cy server (see Release.)
+func (c *Cmd) PTY() (ptmx *os.File, pid int, err error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if c.ptmx == nil || c.proc == nil {
+		return nil, 0, fmt.Errorf("process is not running")
+	}
+
+	return c.ptmx, c.proc.Pid, nil
+}
+
+// Release relinquishes control of the command's process once it has been
+// handed to another cy server. After Release is called, the Cmd no longer
+// reads from the pseudo-terminal and killing the Cmd no longer kills the
+// process.
+func (c *Cmd) Release() {
+	c.Lock()
+	c.released = true
+	c.readState.Broadcast()
+	c.Unlock()
+}
+
+// Pause stops reading from the pseudo-terminal, interrupting a read that is
+// in progress, and waits until the caller of Read has handled all of the
+// output that was already read. Output the process writes while the Cmd is
+// paused stays in the pseudo-terminal until Resume is called or it is read
+// by another cy server after Release.
+func (c *Cmd) Pause(ctx context.Context) error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.paused = true
+	if c.ptmx != nil {
+		err := c.ptmx.SetReadDeadline(time.Now())
+		if err != nil {
+			c.resume()
+			return err
+		}
+	}
+
+	// Wake up the loop below if `ctx` is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			c.Lock()
+			c.readState.Broadcast()
+			c.Unlock()
+		}
+	}()
+
+	for c.reading && ctx.Err() == nil {
+		c.readState.Wait()
+	}
+
+	if ctx.Err() != nil {
+		c.resume()
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// Resume starts reading from the pseudo-terminal again after Pause.
+func (c *Cmd) Resume() {
+	c.Lock()
+	c.resume()
+	c.Unlock()
+}
+
+// resume is Resume without locking. The caller must hold the lock on the Cmd.
+func (c *Cmd) resume() {
+	c.paused = false
+	if c.ptmx != nil {
+		_ = c.ptmx.SetReadDeadline(time.Time{})
+	}
+	c.readState.Broadcast()
+}
+
+// pollable returns a duplicate of `file` in non-blocking mode, which is
+// necessary for read deadlines to interrupt a read that is in progress.
+// Files created by the pty package are always in blocking mode.
+func pollable(file *os.File) (*os.File, error) {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		fd     int
+		dupErr error
+	)
+	err = conn.Control(func(original uintptr) {
+		fd, dupErr = unix.FcntlInt(original, unix.F_DUPFD_CLOEXEC, 0)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dupErr != nil {
+		return nil, dupErr
+	}
+
+	err = unix.SetNonblock(fd, true)
+	if err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	return os.NewFile(uintptr(fd), file.Name()), nil
+}
+
 func (c *Cmd) setStatus(status CmdStatus) {
 	c.Lock()
 	c.status = status
@@ -103,6 +270,18 @@ func (c *Cmd) runPty(ctx context.Context) (chan error, error) {
 			options.Args...,
 		)
 		cmd.Dir = options.Directory
+		cmd.Cancel = func() error {
+			c.RLock()
+			released := c.released
+			c.RUnlock()
+
+			// The process belongs to another server now
+			if released {
+				return nil
+			}
+
+			return cmd.Process.Kill()
+		}
 		cmd.Env = append(
 			os.Environ(),
 			// TODO(cfoust): 08/08/23 this is complicated
@@ -117,7 +296,7 @@ func (c *Cmd) runPty(ctx context.Context) (chan error, error) {
 			)
 		}
 
-		fd, err := pty.StartWithSize(
+		original, err := pty.StartWithSize(
 			cmd,
 			&pty.Winsize{
 				Rows: uint16(size.R),
@@ -126,6 +305,16 @@ func (c *Cmd) runPty(ctx context.Context) (chan error, error) {
 		)
 		if err != nil {
 			started <- err
+			return
+		}
+
+		fd, err := pollable(original)
+		original.Close()
+		if err != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			started <- err
+			return
 		}
 
 		started <- nil
@@ -147,8 +336,40 @@ func (c *Cmd) runPty(ctx context.Context) (chan error, error) {
 	return done, nil
 }
 
+// runAdopted takes over a process started by another cy server and only
+// returns when it's finished. We can't wait on the process, since it isn't
+// our child, so instead we wait for its pseudo-terminal to hang up, which
+// Read detects.
+func (c *Cmd) runAdopted(ctx context.Context, adopted *adoptedProcess) error {
+	defer adopted.ptmx.Close()
+
+	select {
+	case <-ctx.Done():
+		c.RLock()
+		released := c.released
+		c.RUnlock()
+
+		// The process belongs to another server now
+		if !released {
+			adopted.proc.Kill()
+		}
+		return ctx.Err()
+	case <-c.hangup:
+		return nil
+	}
+}
+
 // Run the pane's command and only return when it's finished.
 func (c *Cmd) run(ctx context.Context) error {
+	c.Lock()
+	adopted := c.adopted
+	c.adopted = nil
+	c.Unlock()
+
+	if adopted != nil {
+		return c.runAdopted(ctx, adopted)
+	}
+
 	started := make(chan error)
 
 	var done chan error
@@ -175,27 +396,67 @@ func (c *Cmd) run(ctx context.Context) error {
 }
 
 func (c *Cmd) Read(p []byte) (n int, err error) {
-	c.RLock()
+	c.Lock()
+	// The caller has handled the output from the previous call to Read
+	c.reading = false
+	c.readState.Broadcast()
+
+	for c.paused && !c.released {
+		c.readState.Wait()
+	}
+
 	var (
 		ptmx      = c.ptmx
 		status    = c.status
 		exitError = c.exitError
+		released  = c.released
 	)
-	c.RUnlock()
+
+	if released {
+		c.Unlock()
+		return 0, io.EOF
+	}
 
 	if exitError != nil {
+		c.Unlock()
 		return 0, exitError
 	}
 
 	if status != CmdStatusStarting && status != CmdStatusHealthy {
+		c.Unlock()
 		return 0, io.EOF
 	}
 
 	if ptmx == nil {
+		c.Unlock()
 		return 0, nil
 	}
 
+	c.reading = true
+	c.Unlock()
+
 	n, err = ptmx.Read(p)
+
+	// Pause interrupted the read
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		if n > 0 {
+			return n, nil
+		}
+		return c.Read(p)
+	}
+
+	if err != nil {
+		c.Lock()
+		c.reading = false
+		c.readState.Broadcast()
+		c.Unlock()
+	}
+
+	// The pseudo-terminal hangs up once the process exits
+	if c.hangup != nil && (err == io.EOF || errors.Is(err, syscall.EIO)) {
+		c.hangupOnce.Do(func() { close(c.hangup) })
+	}
+
 	if err == io.EOF {
 		c.Lock()
 		c.ptmx = nil
@@ -329,15 +590,37 @@ func (c *Cmd) waitHealthy(ctx context.Context) error {
 	return <-errc
 }
 
-func NewCmd(ctx context.Context, options CmdOptions, size Size) (*Cmd, error) {
+func newCmd(
+	ctx context.Context,
+	options CmdOptions,
+	size Size,
+	adopted *adoptedProcess,
+) (*Cmd, error) {
 	lifetime := util.NewLifetime(ctx)
 	cmd := Cmd{
 		Lifetime:      lifetime,
 		status:        CmdStatusStarting,
 		options:       options,
 		size:          size,
+		adopted:       adopted,
 		statusUpdates: util.NewPublisher[CmdStatus](),
 	}
+	cmd.readState = sync.NewCond(&cmd.RWMutex)
+
+	// Adopted processes are already running, so there's nothing to wait
+	// for
+	if adopted != nil {
+		cmd.status = CmdStatusHealthy
+		cmd.ptmx = adopted.ptmx
+		cmd.proc = adopted.proc
+		cmd.hangup = make(chan struct{})
+	}
+
+	// A Cmd that is killed while paused should not block its reader
+	go func() {
+		<-lifetime.Ctx().Done()
+		cmd.Resume()
+	}()
 
 	if options.Restart {
 		go cmd.spin(lifetime.Ctx())
@@ -345,6 +628,10 @@ func NewCmd(ctx context.Context, options CmdOptions, size Size) (*Cmd, error) {
 		go cmd.runOnce(lifetime.Ctx())
 	}
 
+	if adopted != nil {
+		return &cmd, nil
+	}
+
 	err := cmd.waitHealthy(lifetime.Ctx())
 	if err != nil {
 		return nil, err
@@ -352,3 +639,36 @@ func NewCmd(ctx context.Context, options CmdOptions, size Size) (*Cmd, error) {
 
 	return &cmd, nil
 }
+
+func NewCmd(ctx context.Context, options CmdOptions, size Size) (*Cmd, error) {
+	return newCmd(ctx, options, size, nil)
+}
+
+// AdoptCmd creates a Cmd for a process that was started by another cy server
+// and handed to this one with (*Cmd).Release. `options` should be the
+// options the process was originally started with; if options.Restart is
+// true, a new process is started after the adopted one exits.
+func AdoptCmd(
+	ctx context.Context,
+	options CmdOptions,
+	size Size,
+	ptmx *os.File,
+	pid int,
+) (*Cmd, error) {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	// The pseudo-terminal we receive may be in blocking mode
+	fd, err := pollable(ptmx)
+	if err != nil {
+		return nil, err
+	}
+	ptmx.Close()
+
+	return newCmd(ctx, options, size, &adoptedProcess{
+		ptmx: fd,
+		proc: proc,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"sync"
 	"testing"
 	"time"
 
@@ -112,3 +113,144 @@ func TestFailed(t *testing.T) {
 	require.Error(t, err)
 	require.Error(t, cmd.exitError)
 }
+
+func TestAdopt(t *testing.T) {
+	options := CmdOptions{
+		Command: "/bin/sh",
+		Args: []string{
+			"-c",
+			"read line && echo got $line && sleep 100",
+		},
+	}
+
+	original, err := NewCmd(
+		context.Background(),
+		options,
+		geom.DEFAULT_SIZE,
+	)
+	require.NoError(t, err)
+
+	ptmx, pid, err := original.PTY()
+	require.NoError(t, err)
+	original.Release()
+
+	// Killing the original Cmd should not kill the process
+	original.Kill()
+	time.Sleep(100 * time.Millisecond)
+
+	cmd, err := AdoptCmd(
+		context.Background(),
+		options,
+		geom.DEFAULT_SIZE,
+		ptmx,
+		pid,
+	)
+	require.NoError(t, err)
+	defer cmd.Kill()
+
+	_, err = cmd.Write([]byte("hello\n"))
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	buffer := make([]byte, 1024)
+	for !bytes.Contains(output.Bytes(), []byte("got hello")) {
+		n, err := cmd.Read(buffer)
+		require.NoError(t, err)
+		output.Write(buffer[:n])
+	}
+}
+
+// lockedBuffer is a bytes.Buffer that can be written and read concurrently.
+type lockedBuffer struct {
+	sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *lockedBuffer) Len() int {
+	b.Lock()
+	defer b.Unlock()
+	return b.buffer.Len()
+}
+
+func TestPause(t *testing.T) {
+	cmd, err := NewCmd(
+		context.Background(),
+		CmdOptions{
+			Command: "/bin/sh",
+			Args: []string{
+				"-c",
+				"while true; do echo output; sleep 0.01; done",
+			},
+		},
+		geom.DEFAULT_SIZE,
+	)
+	require.NoError(t, err)
+	defer cmd.Kill()
+
+	var output lockedBuffer
+	go io.Copy(&output, cmd)
+
+	require.Eventually(t, func() bool {
+		return output.Len() > 0
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, cmd.Pause(context.Background()))
+
+	// Nothing is read while the Cmd is paused
+	length := output.Len()
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, length, output.Len())
+
+	cmd.Resume()
+	require.Eventually(t, func() bool {
+		return output.Len() > length
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestAdoptExit(t *testing.T) {
+	options := CmdOptions{
+		Command: "/bin/sh",
+		Args: []string{
+			"-c",
+			"read line && exit 0",
+		},
+	}
+
+	original, err := NewCmd(
+		context.Background(),
+		options,
+		geom.DEFAULT_SIZE,
+	)
+	require.NoError(t, err)
+
+	ptmx, pid, err := original.PTY()
+	require.NoError(t, err)
+	original.Release()
+
+	cmd, err := AdoptCmd(
+		context.Background(),
+		options,
+		geom.DEFAULT_SIZE,
+		ptmx,
+		pid,
+	)
+	require.NoError(t, err)
+	defer cmd.Kill()
+
+	go io.Copy(io.Discard, cmd)
+
+	// The process exiting is detected through the pseudo-terminal
+	_, err = cmd.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		cmd.RLock()
+		defer cmd.RUnlock()
+		return cmd.status == CmdStatusComplete
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -3,6 +3,7 @@ package renderer
 import (
 	"context"
 	"io"
+	"sync"
 
 	"github.com/cfoust/cy/pkg/emu"
 	"github.com/cfoust/cy/pkg/geom"
@@ -11,6 +12,7 @@ import (
 	"github.com/cfoust/cy/pkg/mux/screen"
 	"github.com/cfoust/cy/pkg/taro"
 
+	"github.com/muesli/termenv"
 	"github.com/xo/terminfo"
 )
 
@@ -27,10 +29,33 @@ type Renderer struct {
 	r      *io.PipeReader
 	w      *io.PipeWriter
 	info   *terminfo.Terminfo
+
+	synchronized bool
+
+	// profileLock protects profile and colors, since the profile can
+	// change while the Renderer is drawing
+	profileLock sync.Mutex
+	profile     termenv.Profile
+	// colors caches the result of converting colors to the color profile
+	colors map[colorKey]emu.Color
 }
 
 var _ mux.Stream = (*Renderer)(nil)
 
+type RendererOption func(*Renderer)
+
+// Providing WithSynchronizedOutput wraps each update in the escape sequences
+// for synchronized output (mode 2026), which prevent terminals that support
+// it from showing partially drawn frames.
+var WithSynchronizedOutput RendererOption = func(r *Renderer) {
+	r.synchronized = true
+}
+
+const (
+	beginSynchronizedUpdate = "\x1b[?2026h"
+	endSynchronizedUpdate   = "\x1b[?2026l"
+)
+
 func (r *Renderer) Kill() {
 	r.screen.Kill()
 }
@@ -72,10 +97,19 @@ func (r *Renderer) poll(ctx context.Context) error {
 		changes := tty.Swap(
 			r.info,
 			tty.Capture(r.raw),
-			r.screen.State(),
+			r.applyProfile(r.screen.State()),
 		)
 		r.raw.Write(changes)
-		_, err := r.w.Write(changes)
+
+		out := changes
+		if r.synchronized && len(changes) > 0 {
+			out = make([]byte, 0, len(changes)+16)
+			out = append(out, beginSynchronizedUpdate...)
+			out = append(out, changes...)
+			out = append(out, endSynchronizedUpdate...)
+		}
+
+		_, err := r.w.Write(out)
 		if err != nil {
 			return err
 		}
@@ -94,6 +128,7 @@ func NewRenderer(
 	info *terminfo.Terminfo,
 	initialSize geom.Size,
 	screen mux.Screen,
+	options ...RendererOption,
 ) *Renderer {
 	r, w := io.Pipe()
 	target := emu.New(
@@ -107,6 +142,11 @@ func NewRenderer(
 		r:      r,
 		w:      w,
 		info:   info,
+		colors: make(map[colorKey]emu.Color),
+	}
+
+	for _, option := range options {
+		option(renderer)
 	}
 
 	go renderer.poll(ctx)
@@ -0,0 +1,133 @@
+package renderer
+
+import (
+	"fmt"
+
+	"github.com/cfoust/cy/pkg/emu"
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/geom/image"
+	"github.com/cfoust/cy/pkg/geom/tty"
+
+	"github.com/muesli/termenv"
+)
+
+// WithProfile makes the Renderer convert every color on the screen to the
+// closest color that terminals with the given color profile can display.
+// By default, colors are written as-is, which is only correct for terminals
+// that support 24-bit color.
+func WithProfile(profile termenv.Profile) RendererOption {
+	return func(r *Renderer) {
+		r.profile = profile
+	}
+}
+
+// SetProfile changes the color profile the Renderer converts colors to. The
+// screen is not redrawn until it next changes.
+func (r *Renderer) SetProfile(profile termenv.Profile) {
+	r.profileLock.Lock()
+	defer r.profileLock.Unlock()
+
+	if r.profile == profile {
+		return
+	}
+
+	r.profile = profile
+	clear(r.colors)
+}
+
+// downsample converts `color` to the closest color that terminals with the
+// color profile `profile` can display. Colors that cannot be displayed at
+// all become the default foreground or background color.
+func downsample(
+	profile termenv.Profile,
+	color emu.Color,
+	isBg bool,
+) emu.Color {
+	if color.Default() {
+		return color
+	}
+
+	var original termenv.Color
+	if r, g, b, ok := color.RGB(); ok {
+		original = termenv.RGBColor(
+			fmt.Sprintf("#%02x%02x%02x", r, g, b),
+		)
+	} else if ansi, ok := color.ANSI(); ok {
+		original = termenv.ANSIColor(ansi)
+	} else if xterm, ok := color.XTerm(); ok {
+		original = termenv.ANSI256Color(xterm)
+	}
+
+	switch converted := profile.Convert(original).(type) {
+	case termenv.RGBColor:
+		return color
+	case termenv.ANSIColor:
+		return emu.ANSIColor(int(converted))
+	case termenv.ANSI256Color:
+		return emu.XTermColor(int(converted))
+	}
+
+	if isBg {
+		return emu.DefaultBG
+	}
+	return emu.DefaultFG
+}
+
+// applyProfile returns a copy of `state` whose colors have been converted
+// according to the Renderer's color profile.
+func (r *Renderer) applyProfile(state *tty.State) *tty.State {
+	r.profileLock.Lock()
+	defer r.profileLock.Unlock()
+
+	if r.profile == termenv.TrueColor {
+		return state
+	}
+
+	// Image.Clone skips transparent cells, which must be preserved
+	cloned := image.New(state.Image.Size())
+	image.CopyRaw(geom.Vec2{}, cloned, state.Image)
+	state = &tty.State{
+		Image:         cloned,
+		Cursor:        state.Cursor,
+		CursorVisible: state.CursorVisible,
+	}
+
+	for row := range state.Image {
+		for col := range state.Image[row] {
+			cell := &state.Image[row][col]
+			cell.FG = r.convert(cell.FG, false)
+			cell.BG = r.convert(cell.BG, true)
+		}
+	}
+
+	return state
+}
+
+type colorKey struct {
+	color emu.Color
+	isBg  bool
+}
+
+// COLOR_CACHE_SIZE is the maximum number of converted colors each Renderer
+// remembers. Programs that use many 24-bit colors would otherwise make the
+// cache grow without limit.
+const COLOR_CACHE_SIZE = 4096
+
+// convert downsamples `color`, caching the result because converting a
+// 24-bit color is relatively expensive. The caller must hold profileLock.
+func (r *Renderer) convert(color emu.Color, isBg bool) emu.Color {
+	key := colorKey{color: color, isBg: isBg}
+	if converted, ok := r.colors[key]; ok {
+		return converted
+	}
+
+	converted := downsample(r.profile, color, isBg)
+
+	// The cache is cleared rather than evicting individual entries,
+	// since the colors on the screen tend to change all at once
+	if len(r.colors) >= COLOR_CACHE_SIZE {
+		clear(r.colors)
+	}
+	r.colors[key] = converted
+	return converted
+}
@@ -0,0 +1,98 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/cfoust/cy/pkg/emu"
+
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownsample(t *testing.T) {
+	red := emu.RGBColor(255, 0, 0)
+
+	for _, test := range []struct {
+		Name     string
+		Profile  termenv.Profile
+		Color    emu.Color
+		IsBg     bool
+		Expected emu.Color
+	}{
+		{
+			Name:     "truecolor",
+			Profile:  termenv.TrueColor,
+			Color:    red,
+			Expected: red,
+		},
+		{
+			Name:     "rgb to 256",
+			Profile:  termenv.ANSI256,
+			Color:    red,
+			Expected: emu.XTermColor(196),
+		},
+		{
+			Name:     "rgb to 16",
+			Profile:  termenv.ANSI,
+			Color:    red,
+			Expected: emu.ANSIColor(9),
+		},
+		{
+			Name:     "256 to 16",
+			Profile:  termenv.ANSI,
+			Color:    emu.XTermColor(196),
+			Expected: emu.ANSIColor(9),
+		},
+		{
+			Name:     "16 unchanged",
+			Profile:  termenv.ANSI,
+			Color:    emu.ANSIColor(2),
+			Expected: emu.ANSIColor(2),
+		},
+		{
+			Name:     "default unchanged",
+			Profile:  termenv.Ascii,
+			Color:    emu.DefaultFG,
+			IsBg:     true,
+			Expected: emu.DefaultFG,
+		},
+		{
+			Name:     "no color foreground",
+			Profile:  termenv.Ascii,
+			Color:    red,
+			Expected: emu.DefaultFG,
+		},
+		{
+			Name:     "no color background",
+			Profile:  termenv.Ascii,
+			Color:    emu.XTermColor(196),
+			IsBg:     true,
+			Expected: emu.DefaultBG,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			require.Equal(
+				t,
+				test.Expected,
+				downsample(test.Profile, test.Color, test.IsBg),
+			)
+		})
+	}
+}
+
+func TestColorCache(t *testing.T) {
+	r := &Renderer{
+		profile: termenv.ANSI256,
+		colors:  make(map[colorKey]emu.Color),
+	}
+
+	for i := 0; i < 2*COLOR_CACHE_SIZE; i++ {
+		color := emu.RGBColor(i>>16, (i>>8)&0xff, i&0xff)
+		require.Equal(
+			t,
+			downsample(r.profile, color, false),
+			r.convert(color, false),
+		)
+		require.LessOrEqual(t, len(r.colors), COLOR_CACHE_SIZE)
+	}
+}
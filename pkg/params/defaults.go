@@ -1,7 +1,10 @@
 package params
 
 import (
+	"fmt"
+	"slices"
 	"sort"
+	"strings"
 )
 
 type DefaultParam struct {
@@ -39,11 +42,96 @@ type defaultParams struct {
 	// The frame used for all new clients. A blank string means a random
 	// frame will be chosen from all frames.
 	DefaultFrame string
+	// A list of regular expressions that {{api input/hint}} uses to find
+	// text on the screen. By default, this matches URLs, file paths,
+	// git commit hashes, and IP addresses.
+	HintPatterns []string
+	// The background color of the labels {{api input/hint}} draws over
+	// each match.
+	HintLabelColor string
+	// The background color {{api input/hint}} uses for the characters of
+	// a label that have already been typed.
+	HintTypedColor string
+	// The background color {{api input/hint}} uses for the text of each
+	// match.
+	HintMatchColor string
+	// The number of changes to each client's layout that are remembered
+	// so that they can be undone with {{api layout/undo}}. If this is 0,
+	// changes to the layout cannot be undone.
+	LayoutHistory int
+	// If this is `true`, changes to the layout that only affect how it
+	// looks, such as renaming a tab or changing the color of a border,
+	// are not recorded in the history used by {{api layout/undo}}.
+	LayoutHistoryIgnoreCosmetic bool
+	// The size of panes whose :pane-size-policy is `"fixed"` in the form
+	// `[rows columns]`.
+	PaneFixedSize []int
+	// The color of the label {{api input/pane}} draws over the pane the
+	// client is attached to.
+	PaneLabelActiveColor string
+	// The color of the labels {{api input/pane}} draws over all other
+	// panes.
+	PaneLabelColor string
+	// The characters {{api input/pane}} uses to label panes, in the order
+	// they are assigned. Panes beyond the length of this string are not
+	// labeled.
+	PaneLabels string
+	// Determines the size of a pane that is shown on more than one
+	// client, since its program can only have one size. This is one of
+	// `"smallest"` (the largest size that fits on every client),
+	// `"largest"` (large enough to fill the largest client), `"active"`
+	// (the size of the client that most recently sent the pane input),
+	// or `"fixed"` (the size in :pane-fixed-size). Clients larger than
+	// the pane fill the rest of its area with their frame. See [pane
+	// sizing](/groups-and-panes.md#pane-sizing) for more information.
+	PaneSizePolicy string
+	// The number of seconds the server keeps a client whose connection
+	// dropped unexpectedly, such as when an SSH connection fails. If the
+	// client reconnects in that time it resumes where it left off, with
+	// its layout, history, and copy buffer intact. If this is 0, clients
+	// are removed as soon as their connection drops.
+	ReconnectTimeout int
 	// If this is `true`, when a pane's process exits or its node is killed
 	// (such as with {{api tree/kill}}), the portion of the layout related
 	// to that node will be removed. This makes cy's layout functionality
 	// work a bit more like tmux.
 	RemovePaneOnExit bool
+	// If this is `true`, entering replay mode by scrolling up with the
+	// mouse also turns on [follow mode](/replay-mode/modes.md#following-new-output).
+	ReplayFollowOnScroll bool
+	// If this is `true`, the layout most recently saved with
+	// {{api layout/save}} is restored whenever a client connects. Panes
+	// in the layout that no longer exist are replaced with new shells.
+	RestoreLayout bool
+	// If this is `true` and a snapshot of a previous cy server exists in
+	// :data-directory, the groups, panes, and layouts it contains are
+	// recreated after cy starts and your configuration has been loaded.
+	// This restarts the command that was running in every pane, so it
+	// is off by default.
+	// See [resurrection](/groups-and-panes.md#resurrection) for more information.
+	Resurrect bool
+	// The number of seconds between each snapshot of the server's state
+	// saved to :data-directory. If this is 0, no snapshots are saved.
+	SnapshotInterval int
+	// The name of a Unix group whose members may connect to the server
+	// with the level of access in :socket-group-access. See [sharing a
+	// server](/cli.md#sharing-a-server) for more information.
+	SocketGroup string
+	// The level of access members of :socket-group have to the server,
+	// which is one of `"full"`, `"read-only"`, or `"replay-only"`.
+	SocketGroupAccess string
+	// The users who may connect to the server. Each entry is a user name
+	// or uid, optionally followed by a colon and the user's level of
+	// access (e.g. `"alice:read-only"`), which is `"full"` if it is not
+	// provided.
+	SocketUsers []string
+	// The environment variables copied from the environment of the client
+	// that creates a pane into the environment of its process, similar to
+	// tmux's `update-environment` option. This keeps variables like
+	// `SSH_AUTH_SOCK` correct in new panes after you reconnect from
+	// somewhere else. Variables the client does not have are inherited
+	// from the server as usual.
+	UpdateEnvironment []string
 	// Whether to avoid blocking on (input/*) calls. Just for testing.
 	skipInput bool
 }
@@ -54,6 +142,64 @@ var (
 		DataDirectory: "",
 		DefaultFrame:  "",
 		DefaultShell:  "/bin/bash",
-		skipInput:     false,
+		HintPatterns: []string{
+			// URLs
+			`(https?|ftp|file)://[^\s'"<>()\[\]]+`,
+			// File paths
+			`(~|\.{1,2})?(/[\w.@+-]+)+/?|[\w.@+-]+(/[\w.@+-]+)+/?`,
+			// Git commit hashes
+			`\b[0-9a-f]{7,40}\b`,
+			// IPv4 addresses, optionally with a port
+			`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`,
+		},
+		HintLabelColor:       "#F25F5C",
+		HintMatchColor:       "3",
+		HintTypedColor:       "#7768AE",
+		LayoutHistory:        100,
+		PaneLabelActiveColor: "#F25F5C",
+		PaneLabelColor:       "#7768AE",
+		PaneLabels:           "1234567890abcdefghijklmnopqrstuvwxyz",
+		PaneSizePolicy:       "smallest",
+		ReconnectTimeout:     60,
+		SnapshotInterval:     30,
+		SocketGroupAccess:    "read-only",
+		UpdateEnvironment: []string{
+			"DISPLAY",
+			"KRB5CCNAME",
+			"SSH_ASKPASS",
+			"SSH_AUTH_SOCK",
+			"SSH_AGENT_PID",
+			"SSH_CONNECTION",
+			"WINDOWID",
+			"XAUTHORITY",
+		},
+		skipInput: false,
 	}
 )
+
+// PANE_SIZE_POLICIES are the valid values of :pane-size-policy.
+var PANE_SIZE_POLICIES = []string{
+	"smallest",
+	"largest",
+	"active",
+	"fixed",
+}
+
+// validate checks parameters that only accept some of the values of their
+// type.
+func validate(key string, value interface{}) error {
+	switch key {
+	case ParamPaneSizePolicy:
+		policy, _ := value.(string)
+		if !slices.Contains(PANE_SIZE_POLICIES, policy) {
+			return fmt.Errorf(
+				"invalid value for :%s: %q, should be one of %s",
+				key,
+				policy,
+				strings.Join(PANE_SIZE_POLICIES, ", "),
+			)
+		}
+	}
+
+	return nil
+}
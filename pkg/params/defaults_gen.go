@@ -8,13 +8,33 @@ import (
 )
 
 const (
-	ParamAnimate          = "animate"
-	ParamAnimations       = "animations"
-	ParamDataDirectory    = "data-directory"
-	ParamDefaultFrame     = "default-frame"
-	ParamDefaultShell     = "default-shell"
-	ParamRemovePaneOnExit = "remove-pane-on-exit"
-	ParamSkipInput        = "---skip-input"
+	ParamAnimate                     = "animate"
+	ParamAnimations                  = "animations"
+	ParamDataDirectory               = "data-directory"
+	ParamDefaultFrame                = "default-frame"
+	ParamDefaultShell                = "default-shell"
+	ParamHintLabelColor              = "hint-label-color"
+	ParamHintMatchColor              = "hint-match-color"
+	ParamHintPatterns                = "hint-patterns"
+	ParamHintTypedColor              = "hint-typed-color"
+	ParamLayoutHistory               = "layout-history"
+	ParamLayoutHistoryIgnoreCosmetic = "layout-history-ignore-cosmetic"
+	ParamPaneFixedSize               = "pane-fixed-size"
+	ParamPaneLabelActiveColor        = "pane-label-active-color"
+	ParamPaneLabelColor              = "pane-label-color"
+	ParamPaneLabels                  = "pane-labels"
+	ParamPaneSizePolicy              = "pane-size-policy"
+	ParamReconnectTimeout            = "reconnect-timeout"
+	ParamRemovePaneOnExit            = "remove-pane-on-exit"
+	ParamReplayFollowOnScroll        = "replay-follow-on-scroll"
+	ParamRestoreLayout               = "restore-layout"
+	ParamResurrect                   = "resurrect"
+	ParamSkipInput                   = "---skip-input"
+	ParamSnapshotInterval            = "snapshot-interval"
+	ParamSocketGroup                 = "socket-group"
+	ParamSocketGroupAccess           = "socket-group-access"
+	ParamSocketUsers                 = "socket-users"
+	ParamUpdateEnvironment           = "update-environment"
 )
 
 func (p *Parameters) Animate() bool {
@@ -107,6 +127,222 @@ func (p *Parameters) SetDefaultShell(value string) {
 	p.set(ParamDefaultShell, value)
 }
 
+func (p *Parameters) HintLabelColor() string {
+	value, ok := p.Get(ParamHintLabelColor)
+	if !ok {
+		return defaults.HintLabelColor
+	}
+
+	realValue, ok := value.(string)
+	if !ok {
+		return defaults.HintLabelColor
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetHintLabelColor(value string) {
+	p.set(ParamHintLabelColor, value)
+}
+
+func (p *Parameters) HintMatchColor() string {
+	value, ok := p.Get(ParamHintMatchColor)
+	if !ok {
+		return defaults.HintMatchColor
+	}
+
+	realValue, ok := value.(string)
+	if !ok {
+		return defaults.HintMatchColor
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetHintMatchColor(value string) {
+	p.set(ParamHintMatchColor, value)
+}
+
+func (p *Parameters) HintPatterns() []string {
+	value, ok := p.Get(ParamHintPatterns)
+	if !ok {
+		return defaults.HintPatterns
+	}
+
+	realValue, ok := value.([]string)
+	if !ok {
+		return defaults.HintPatterns
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetHintPatterns(value []string) {
+	p.set(ParamHintPatterns, value)
+}
+
+func (p *Parameters) HintTypedColor() string {
+	value, ok := p.Get(ParamHintTypedColor)
+	if !ok {
+		return defaults.HintTypedColor
+	}
+
+	realValue, ok := value.(string)
+	if !ok {
+		return defaults.HintTypedColor
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetHintTypedColor(value string) {
+	p.set(ParamHintTypedColor, value)
+}
+
+func (p *Parameters) LayoutHistory() int {
+	value, ok := p.Get(ParamLayoutHistory)
+	if !ok {
+		return defaults.LayoutHistory
+	}
+
+	realValue, ok := value.(int)
+	if !ok {
+		return defaults.LayoutHistory
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetLayoutHistory(value int) {
+	p.set(ParamLayoutHistory, value)
+}
+
+func (p *Parameters) LayoutHistoryIgnoreCosmetic() bool {
+	value, ok := p.Get(ParamLayoutHistoryIgnoreCosmetic)
+	if !ok {
+		return defaults.LayoutHistoryIgnoreCosmetic
+	}
+
+	realValue, ok := value.(bool)
+	if !ok {
+		return defaults.LayoutHistoryIgnoreCosmetic
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetLayoutHistoryIgnoreCosmetic(value bool) {
+	p.set(ParamLayoutHistoryIgnoreCosmetic, value)
+}
+
+func (p *Parameters) PaneFixedSize() []int {
+	value, ok := p.Get(ParamPaneFixedSize)
+	if !ok {
+		return defaults.PaneFixedSize
+	}
+
+	realValue, ok := value.([]int)
+	if !ok {
+		return defaults.PaneFixedSize
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetPaneFixedSize(value []int) {
+	p.set(ParamPaneFixedSize, value)
+}
+
+func (p *Parameters) PaneLabelActiveColor() string {
+	value, ok := p.Get(ParamPaneLabelActiveColor)
+	if !ok {
+		return defaults.PaneLabelActiveColor
+	}
+
+	realValue, ok := value.(string)
+	if !ok {
+		return defaults.PaneLabelActiveColor
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetPaneLabelActiveColor(value string) {
+	p.set(ParamPaneLabelActiveColor, value)
+}
+
+func (p *Parameters) PaneLabelColor() string {
+	value, ok := p.Get(ParamPaneLabelColor)
+	if !ok {
+		return defaults.PaneLabelColor
+	}
+
+	realValue, ok := value.(string)
+	if !ok {
+		return defaults.PaneLabelColor
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetPaneLabelColor(value string) {
+	p.set(ParamPaneLabelColor, value)
+}
+
+func (p *Parameters) PaneLabels() string {
+	value, ok := p.Get(ParamPaneLabels)
+	if !ok {
+		return defaults.PaneLabels
+	}
+
+	realValue, ok := value.(string)
+	if !ok {
+		return defaults.PaneLabels
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetPaneLabels(value string) {
+	p.set(ParamPaneLabels, value)
+}
+
+func (p *Parameters) PaneSizePolicy() string {
+	value, ok := p.Get(ParamPaneSizePolicy)
+	if !ok {
+		return defaults.PaneSizePolicy
+	}
+
+	realValue, ok := value.(string)
+	if !ok {
+		return defaults.PaneSizePolicy
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetPaneSizePolicy(value string) {
+	p.set(ParamPaneSizePolicy, value)
+}
+
+func (p *Parameters) ReconnectTimeout() int {
+	value, ok := p.Get(ParamReconnectTimeout)
+	if !ok {
+		return defaults.ReconnectTimeout
+	}
+
+	realValue, ok := value.(int)
+	if !ok {
+		return defaults.ReconnectTimeout
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetReconnectTimeout(value int) {
+	p.set(ParamReconnectTimeout, value)
+}
+
 func (p *Parameters) RemovePaneOnExit() bool {
 	value, ok := p.Get(ParamRemovePaneOnExit)
 	if !ok {
@@ -125,6 +361,60 @@ func (p *Parameters) SetRemovePaneOnExit(value bool) {
 	p.set(ParamRemovePaneOnExit, value)
 }
 
+func (p *Parameters) ReplayFollowOnScroll() bool {
+	value, ok := p.Get(ParamReplayFollowOnScroll)
+	if !ok {
+		return defaults.ReplayFollowOnScroll
+	}
+
+	realValue, ok := value.(bool)
+	if !ok {
+		return defaults.ReplayFollowOnScroll
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetReplayFollowOnScroll(value bool) {
+	p.set(ParamReplayFollowOnScroll, value)
+}
+
+func (p *Parameters) RestoreLayout() bool {
+	value, ok := p.Get(ParamRestoreLayout)
+	if !ok {
+		return defaults.RestoreLayout
+	}
+
+	realValue, ok := value.(bool)
+	if !ok {
+		return defaults.RestoreLayout
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetRestoreLayout(value bool) {
+	p.set(ParamRestoreLayout, value)
+}
+
+func (p *Parameters) Resurrect() bool {
+	value, ok := p.Get(ParamResurrect)
+	if !ok {
+		return defaults.Resurrect
+	}
+
+	realValue, ok := value.(bool)
+	if !ok {
+		return defaults.Resurrect
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetResurrect(value bool) {
+	p.set(ParamResurrect, value)
+}
+
 func (p *Parameters) SkipInput() bool {
 	value, ok := p.Get(ParamSkipInput)
 	if !ok {
@@ -143,6 +433,96 @@ func (p *Parameters) SetSkipInput(value bool) {
 	p.set(ParamSkipInput, value)
 }
 
+func (p *Parameters) SnapshotInterval() int {
+	value, ok := p.Get(ParamSnapshotInterval)
+	if !ok {
+		return defaults.SnapshotInterval
+	}
+
+	realValue, ok := value.(int)
+	if !ok {
+		return defaults.SnapshotInterval
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetSnapshotInterval(value int) {
+	p.set(ParamSnapshotInterval, value)
+}
+
+func (p *Parameters) SocketGroup() string {
+	value, ok := p.Get(ParamSocketGroup)
+	if !ok {
+		return defaults.SocketGroup
+	}
+
+	realValue, ok := value.(string)
+	if !ok {
+		return defaults.SocketGroup
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetSocketGroup(value string) {
+	p.set(ParamSocketGroup, value)
+}
+
+func (p *Parameters) SocketGroupAccess() string {
+	value, ok := p.Get(ParamSocketGroupAccess)
+	if !ok {
+		return defaults.SocketGroupAccess
+	}
+
+	realValue, ok := value.(string)
+	if !ok {
+		return defaults.SocketGroupAccess
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetSocketGroupAccess(value string) {
+	p.set(ParamSocketGroupAccess, value)
+}
+
+func (p *Parameters) SocketUsers() []string {
+	value, ok := p.Get(ParamSocketUsers)
+	if !ok {
+		return defaults.SocketUsers
+	}
+
+	realValue, ok := value.([]string)
+	if !ok {
+		return defaults.SocketUsers
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetSocketUsers(value []string) {
+	p.set(ParamSocketUsers, value)
+}
+
+func (p *Parameters) UpdateEnvironment() []string {
+	value, ok := p.Get(ParamUpdateEnvironment)
+	if !ok {
+		return defaults.UpdateEnvironment
+	}
+
+	realValue, ok := value.([]string)
+	if !ok {
+		return defaults.UpdateEnvironment
+	}
+
+	return realValue
+}
+
+func (p *Parameters) SetUpdateEnvironment(value []string) {
+	p.set(ParamUpdateEnvironment, value)
+}
+
 func (p *Parameters) isDefault(key string) bool {
 	switch key {
 	case ParamAnimate:
@@ -155,23 +535,316 @@ func (p *Parameters) isDefault(key string) bool {
 		return true
 	case ParamDefaultShell:
 		return true
+	case ParamHintLabelColor:
+		return true
+	case ParamHintMatchColor:
+		return true
+	case ParamHintPatterns:
+		return true
+	case ParamHintTypedColor:
+		return true
+	case ParamLayoutHistory:
+		return true
+	case ParamLayoutHistoryIgnoreCosmetic:
+		return true
+	case ParamPaneFixedSize:
+		return true
+	case ParamPaneLabelActiveColor:
+		return true
+	case ParamPaneLabelColor:
+		return true
+	case ParamPaneLabels:
+		return true
+	case ParamPaneSizePolicy:
+		return true
+	case ParamReconnectTimeout:
+		return true
 	case ParamRemovePaneOnExit:
 		return true
+	case ParamReplayFollowOnScroll:
+		return true
+	case ParamRestoreLayout:
+		return true
+	case ParamResurrect:
+		return true
 	case ParamSkipInput:
 		return true
+	case ParamSnapshotInterval:
+		return true
+	case ParamSocketGroup:
+		return true
+	case ParamSocketGroupAccess:
+		return true
+	case ParamSocketUsers:
+		return true
+	case ParamUpdateEnvironment:
+		return true
 
 	}
 	return false
 }
 
-func (p *Parameters) setDefault(key string, value interface{}) error {
-	janetValue, janetOk := value.(*janet.Value)
-	switch key {
-	case ParamAnimate:
+func (p *Parameters) setDefault(key string, value interface{}) error {
+	janetValue, janetOk := value.(*janet.Value)
+	switch key {
+	case ParamAnimate:
+		if !janetOk {
+			realValue, ok := value.(bool)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamAnimate, should be bool")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated bool
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :animate: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamAnimations:
+		if !janetOk {
+			realValue, ok := value.([]string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamAnimations, should be []string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated []string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :animations: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamDataDirectory:
+		if !janetOk {
+			realValue, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamDataDirectory, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :data-directory: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamDefaultFrame:
+		if !janetOk {
+			realValue, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamDefaultFrame, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :default-frame: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamDefaultShell:
+		if !janetOk {
+			realValue, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamDefaultShell, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :default-shell: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamHintLabelColor:
+		if !janetOk {
+			realValue, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamHintLabelColor, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :hint-label-color: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamHintMatchColor:
+		if !janetOk {
+			realValue, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamHintMatchColor, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :hint-match-color: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamHintPatterns:
+		if !janetOk {
+			realValue, ok := value.([]string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamHintPatterns, should be []string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated []string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :hint-patterns: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamHintTypedColor:
+		if !janetOk {
+			realValue, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamHintTypedColor, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :hint-typed-color: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamLayoutHistory:
+		if !janetOk {
+			realValue, ok := value.(int)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamLayoutHistory, should be int")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated int
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :layout-history: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamLayoutHistoryIgnoreCosmetic:
 		if !janetOk {
 			realValue, ok := value.(bool)
 			if !ok {
-				return fmt.Errorf("invalid value for ParamAnimate, should be bool")
+				return fmt.Errorf("invalid value for ParamLayoutHistoryIgnoreCosmetic, should be bool")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
 			}
 			p.set(key, realValue)
 			return nil
@@ -181,35 +854,47 @@ func (p *Parameters) setDefault(key string, value interface{}) error {
 		err := janetValue.Unmarshal(&translated)
 		if err != nil {
 			janetValue.Free()
-			return fmt.Errorf("invalid value for :animate: %s", err)
+			return fmt.Errorf("invalid value for :layout-history-ignore-cosmetic: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
 		}
 		p.set(key, translated)
 		return nil
 
-	case ParamAnimations:
+	case ParamPaneFixedSize:
 		if !janetOk {
-			realValue, ok := value.([]string)
+			realValue, ok := value.([]int)
 			if !ok {
-				return fmt.Errorf("invalid value for ParamAnimations, should be []string")
+				return fmt.Errorf("invalid value for ParamPaneFixedSize, should be []int")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
 			}
 			p.set(key, realValue)
 			return nil
 		}
 
-		var translated []string
+		var translated []int
 		err := janetValue.Unmarshal(&translated)
 		if err != nil {
 			janetValue.Free()
-			return fmt.Errorf("invalid value for :animations: %s", err)
+			return fmt.Errorf("invalid value for :pane-fixed-size: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
 		}
 		p.set(key, translated)
 		return nil
 
-	case ParamDataDirectory:
+	case ParamPaneLabelActiveColor:
 		if !janetOk {
 			realValue, ok := value.(string)
 			if !ok {
-				return fmt.Errorf("invalid value for ParamDataDirectory, should be string")
+				return fmt.Errorf("invalid value for ParamPaneLabelActiveColor, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
 			}
 			p.set(key, realValue)
 			return nil
@@ -219,16 +904,22 @@ func (p *Parameters) setDefault(key string, value interface{}) error {
 		err := janetValue.Unmarshal(&translated)
 		if err != nil {
 			janetValue.Free()
-			return fmt.Errorf("invalid value for :data-directory: %s", err)
+			return fmt.Errorf("invalid value for :pane-label-active-color: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
 		}
 		p.set(key, translated)
 		return nil
 
-	case ParamDefaultFrame:
+	case ParamPaneLabelColor:
 		if !janetOk {
 			realValue, ok := value.(string)
 			if !ok {
-				return fmt.Errorf("invalid value for ParamDefaultFrame, should be string")
+				return fmt.Errorf("invalid value for ParamPaneLabelColor, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
 			}
 			p.set(key, realValue)
 			return nil
@@ -238,16 +929,22 @@ func (p *Parameters) setDefault(key string, value interface{}) error {
 		err := janetValue.Unmarshal(&translated)
 		if err != nil {
 			janetValue.Free()
-			return fmt.Errorf("invalid value for :default-frame: %s", err)
+			return fmt.Errorf("invalid value for :pane-label-color: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
 		}
 		p.set(key, translated)
 		return nil
 
-	case ParamDefaultShell:
+	case ParamPaneLabels:
 		if !janetOk {
 			realValue, ok := value.(string)
 			if !ok {
-				return fmt.Errorf("invalid value for ParamDefaultShell, should be string")
+				return fmt.Errorf("invalid value for ParamPaneLabels, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
 			}
 			p.set(key, realValue)
 			return nil
@@ -257,7 +954,60 @@ func (p *Parameters) setDefault(key string, value interface{}) error {
 		err := janetValue.Unmarshal(&translated)
 		if err != nil {
 			janetValue.Free()
-			return fmt.Errorf("invalid value for :default-shell: %s", err)
+			return fmt.Errorf("invalid value for :pane-labels: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamPaneSizePolicy:
+		if !janetOk {
+			realValue, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamPaneSizePolicy, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :pane-size-policy: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamReconnectTimeout:
+		if !janetOk {
+			realValue, ok := value.(int)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamReconnectTimeout, should be int")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated int
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :reconnect-timeout: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
 		}
 		p.set(key, translated)
 		return nil
@@ -268,6 +1018,9 @@ func (p *Parameters) setDefault(key string, value interface{}) error {
 			if !ok {
 				return fmt.Errorf("invalid value for ParamRemovePaneOnExit, should be bool")
 			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
 			p.set(key, realValue)
 			return nil
 		}
@@ -278,6 +1031,84 @@ func (p *Parameters) setDefault(key string, value interface{}) error {
 			janetValue.Free()
 			return fmt.Errorf("invalid value for :remove-pane-on-exit: %s", err)
 		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamReplayFollowOnScroll:
+		if !janetOk {
+			realValue, ok := value.(bool)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamReplayFollowOnScroll, should be bool")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated bool
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :replay-follow-on-scroll: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamRestoreLayout:
+		if !janetOk {
+			realValue, ok := value.(bool)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamRestoreLayout, should be bool")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated bool
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :restore-layout: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamResurrect:
+		if !janetOk {
+			realValue, ok := value.(bool)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamResurrect, should be bool")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated bool
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :resurrect: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
 		p.set(key, translated)
 		return nil
 
@@ -287,12 +1118,140 @@ func (p *Parameters) setDefault(key string, value interface{}) error {
 			if !ok {
 				return fmt.Errorf("invalid value for ParamSkipInput, should be bool")
 			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
 			p.set(key, realValue)
 			return nil
 		}
 
 		return fmt.Errorf(":---skip-input is a protected parameter")
 
+	case ParamSnapshotInterval:
+		if !janetOk {
+			realValue, ok := value.(int)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamSnapshotInterval, should be int")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated int
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :snapshot-interval: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamSocketGroup:
+		if !janetOk {
+			realValue, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamSocketGroup, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :socket-group: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamSocketGroupAccess:
+		if !janetOk {
+			realValue, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamSocketGroupAccess, should be string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :socket-group-access: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamSocketUsers:
+		if !janetOk {
+			realValue, ok := value.([]string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamSocketUsers, should be []string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated []string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :socket-users: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
+	case ParamUpdateEnvironment:
+		if !janetOk {
+			realValue, ok := value.([]string)
+			if !ok {
+				return fmt.Errorf("invalid value for ParamUpdateEnvironment, should be []string")
+			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
+			p.set(key, realValue)
+			return nil
+		}
+
+		var translated []string
+		err := janetValue.Unmarshal(&translated)
+		if err != nil {
+			janetValue.Free()
+			return fmt.Errorf("invalid value for :update-environment: %s", err)
+		}
+		if err := validate(key, translated); err != nil {
+			return err
+		}
+		p.set(key, translated)
+		return nil
+
 	}
 	return nil
 }
@@ -324,10 +1283,110 @@ func init() {
 			Docstring: "The default shell with which to start panes. Defaults to the value\nof `$SHELL` on startup.",
 			Default:   defaults.DefaultShell,
 		},
+		{
+			Name:      "hint-label-color",
+			Docstring: "The background color of the labels {{api input/hint}} draws over\neach match.",
+			Default:   defaults.HintLabelColor,
+		},
+		{
+			Name:      "hint-match-color",
+			Docstring: "The background color {{api input/hint}} uses for the text of each\nmatch.",
+			Default:   defaults.HintMatchColor,
+		},
+		{
+			Name:      "hint-patterns",
+			Docstring: "A list of regular expressions that {{api input/hint}} uses to find\ntext on the screen. By default, this matches URLs, file paths,\ngit commit hashes, and IP addresses.",
+			Default:   defaults.HintPatterns,
+		},
+		{
+			Name:      "hint-typed-color",
+			Docstring: "The background color {{api input/hint}} uses for the characters of\na label that have already been typed.",
+			Default:   defaults.HintTypedColor,
+		},
+		{
+			Name:      "layout-history",
+			Docstring: "The number of changes to each client's layout that are remembered\nso that they can be undone with {{api layout/undo}}. If this is 0,\nchanges to the layout cannot be undone.",
+			Default:   defaults.LayoutHistory,
+		},
+		{
+			Name:      "layout-history-ignore-cosmetic",
+			Docstring: "If this is `true`, changes to the layout that only affect how it\nlooks, such as renaming a tab or changing the color of a border,\nare not recorded in the history used by {{api layout/undo}}.",
+			Default:   defaults.LayoutHistoryIgnoreCosmetic,
+		},
+		{
+			Name:      "pane-fixed-size",
+			Docstring: "The size of panes whose :pane-size-policy is `\"fixed\"` in the form\n`[rows columns]`.",
+			Default:   defaults.PaneFixedSize,
+		},
+		{
+			Name:      "pane-label-active-color",
+			Docstring: "The color of the label {{api input/pane}} draws over the pane the\nclient is attached to.",
+			Default:   defaults.PaneLabelActiveColor,
+		},
+		{
+			Name:      "pane-label-color",
+			Docstring: "The color of the labels {{api input/pane}} draws over all other\npanes.",
+			Default:   defaults.PaneLabelColor,
+		},
+		{
+			Name:      "pane-labels",
+			Docstring: "The characters {{api input/pane}} uses to label panes, in the order\nthey are assigned. Panes beyond the length of this string are not\nlabeled.",
+			Default:   defaults.PaneLabels,
+		},
+		{
+			Name:      "pane-size-policy",
+			Docstring: "Determines the size of a pane that is shown on more than one\nclient, since its program can only have one size. This is one of\n`\"smallest\"` (the largest size that fits on every client),\n`\"largest\"` (large enough to fill the largest client), `\"active\"`\n(the size of the client that most recently sent the pane input),\nor `\"fixed\"` (the size in :pane-fixed-size). Clients larger than\nthe pane fill the rest of its area with their frame. See [pane\nsizing](/groups-and-panes.md#pane-sizing) for more information.",
+			Default:   defaults.PaneSizePolicy,
+		},
+		{
+			Name:      "reconnect-timeout",
+			Docstring: "The number of seconds the server keeps a client whose connection\ndropped unexpectedly, such as when an SSH connection fails. If the\nclient reconnects in that time it resumes where it left off, with\nits layout, history, and copy buffer intact. If this is 0, clients\nare removed as soon as their connection drops.",
+			Default:   defaults.ReconnectTimeout,
+		},
 		{
 			Name:      "remove-pane-on-exit",
 			Docstring: "If this is `true`, when a pane's process exits or its node is killed\n(such as with {{api tree/kill}}), the portion of the layout related\nto that node will be removed. This makes cy's layout functionality\nwork a bit more like tmux.",
 			Default:   defaults.RemovePaneOnExit,
 		},
+		{
+			Name:      "replay-follow-on-scroll",
+			Docstring: "If this is `true`, entering replay mode by scrolling up with the\nmouse also turns on [follow mode](/replay-mode/modes.md#following-new-output).",
+			Default:   defaults.ReplayFollowOnScroll,
+		},
+		{
+			Name:      "restore-layout",
+			Docstring: "If this is `true`, the layout most recently saved with\n{{api layout/save}} is restored whenever a client connects. Panes\nin the layout that no longer exist are replaced with new shells.",
+			Default:   defaults.RestoreLayout,
+		},
+		{
+			Name:      "resurrect",
+			Docstring: "If this is `true` and a snapshot of a previous cy server exists in\n:data-directory, the groups, panes, and layouts it contains are\nrecreated after cy starts and your configuration has been loaded.\nThis restarts the command that was running in every pane, so it\nis off by default.\nSee [resurrection](/groups-and-panes.md#resurrection) for more information.",
+			Default:   defaults.Resurrect,
+		},
+		{
+			Name:      "snapshot-interval",
+			Docstring: "The number of seconds between each snapshot of the server's state\nsaved to :data-directory. If this is 0, no snapshots are saved.",
+			Default:   defaults.SnapshotInterval,
+		},
+		{
+			Name:      "socket-group",
+			Docstring: "The name of a Unix group whose members may connect to the server\nwith the level of access in :socket-group-access. See [sharing a\nserver](/cli.md#sharing-a-server) for more information.",
+			Default:   defaults.SocketGroup,
+		},
+		{
+			Name:      "socket-group-access",
+			Docstring: "The level of access members of :socket-group have to the server,\nwhich is one of `\"full\"`, `\"read-only\"`, or `\"replay-only\"`.",
+			Default:   defaults.SocketGroupAccess,
+		},
+		{
+			Name:      "socket-users",
+			Docstring: "The users who may connect to the server. Each entry is a user name\nor uid, optionally followed by a colon and the user's level of\naccess (e.g. `\"alice:read-only\"`), which is `\"full\"` if it is not\nprovided.",
+			Default:   defaults.SocketUsers,
+		},
+		{
+			Name:      "update-environment",
+			Docstring: "The environment variables copied from the environment of the client\nthat creates a pane into the environment of its process, similar to\ntmux's `update-environment` option. This keeps variables like\n`SSH_AUTH_SOCK` correct in new panes after you reconnect from\nsomewhere else. Variables the client does not have are inherited\nfrom the server as usual.",
+			Default:   defaults.UpdateEnvironment,
+		},
 	}
 }
@@ -116,6 +116,9 @@ func (p *Parameters) setDefault(key string, value interface{}) error {
 			if !ok {
 			    return fmt.Errorf("invalid value for {{.Constant}}, should be {{.Type}}")
 			}
+			if err := validate(key, realValue); err != nil {
+				return err
+			}
 			p.set(key, realValue)
 			return nil
 		}
@@ -129,6 +132,9 @@ func (p *Parameters) setDefault(key string, value interface{}) error {
 				janetValue.Free()
 				return fmt.Errorf("invalid value for :{{.Kebab}}: %s", err)
 		}
+		if err := validate(key, translated); err != nil {
+				return err
+		}
 		p.set(key, translated)
 		return nil
 {{end}}
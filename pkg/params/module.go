@@ -1,6 +1,8 @@
 package params
 
 import (
+	"sort"
+
 	"github.com/cfoust/cy/pkg/janet"
 
 	"github.com/sasha-s/go-deadlock"
@@ -54,6 +56,20 @@ func (p *Parameters) Get(key string) (value interface{}, ok bool) {
 	return nil, false
 }
 
+// Keys returns the keys of all of the parameters set directly on p, ignoring
+// those set on its ancestors.
+func (p *Parameters) Keys() (keys []string) {
+	p.RLock()
+	defer p.RUnlock()
+
+	for key := range p.table {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
 func (p *Parameters) NewChild() *Parameters {
 	child := New()
 	child.parent = p
@@ -38,6 +38,12 @@ type CopyEvent struct {
 	Text string
 }
 
+// ErrorEvent is published when an action fails in a way the user should know
+// about.
+type ErrorEvent struct {
+	Err error
+}
+
 type Mode uint8
 
 const (
@@ -74,6 +80,17 @@ const (
 	ActionCommandBackward
 	ActionCommandSelectForward
 	ActionCommandSelectBackward
+	// Prompt the user for a time to jump to or, if Arg is provided, jump
+	// to it directly
+	ActionGotoTime
+	// Toggle follow mode, which keeps the replay at the newest output
+	ActionFollow
+	// Mark the current location in time
+	ActionMark
+	// Compare the screen at the mark with the current one
+	ActionDiff
+	// Copy a range of events exported in the format named by Arg
+	ActionExport
 
 	//////////////////////////////////////////////////////////////////
 	// ╺┳╸┏┳┓╻ ╻╻ ╻   ┏━╸┏━┓┏━┓╻ ╻   ┏┳┓┏━┓╺┳┓┏━╸
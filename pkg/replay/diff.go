@@ -0,0 +1,106 @@
+package replay
+
+import (
+	"github.com/cfoust/cy/pkg/emu"
+	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/replay/diff"
+	"github.com/cfoust/cy/pkg/replay/player"
+	"github.com/cfoust/cy/pkg/sessions"
+	"github.com/cfoust/cy/pkg/taro"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lineStrings returns the text of each line in `lines`.
+func lineStrings(lines []emu.Line) (text []string) {
+	for _, line := range lines {
+		text = append(text, line.String())
+	}
+	return
+}
+
+// getLines returns the text of each line on the screen of `terminal` without
+// trailing whitespace. Empty lines at the bottom of the screen are omitted.
+func getLines(terminal emu.Terminal) []string {
+	return lineStrings(trimLines(terminal.Screen()))
+}
+
+// ScreenAt returns the lines on the screen immediately after the event at
+// `index` (and byte offset `offset`) in `events`.
+func ScreenAt(events []sessions.Event, index, offset int) []string {
+	return getLines(player.At(events, index, offset))
+}
+
+// OutputLines interprets `data`, the raw output of a program, as it would
+// appear on a terminal with `width` columns and returns the resulting lines.
+func OutputLines(data []byte, width int) []string {
+	return lineStrings(renderOutput(data, width))
+}
+
+type diffEvent struct {
+	lines []diff.Line
+}
+
+// setMark marks the current location so that it can be compared with another
+// one later.
+func (r *Replay) setMark() {
+	location := r.Location()
+	r.mark = &location
+}
+
+// showDiff compares the screen at the mark with the screen at the current
+// location.
+func (r *Replay) showDiff() tea.Cmd {
+	if r.mark == nil {
+		return nil
+	}
+
+	events := r.Events()
+	mark := *r.mark
+	current := getLines(r.Terminal)
+	return func() tea.Msg {
+		return diffEvent{
+			lines: diff.Lines(
+				ScreenAt(events, mark.Index, mark.Offset),
+				current,
+			),
+		}
+	}
+}
+
+func (r *Replay) handleDiffAction(msg ActionEvent) (taro.Model, tea.Cmd) {
+	viewport := r.viewport
+	switch msg.Type {
+	case ActionQuit:
+		r.diff = nil
+	case ActionDiff:
+		r.isDiffSplit = !r.isDiffSplit
+		r.diffOffset = 0
+	case ActionCursorDown, ActionScrollDown:
+		r.scrollDiff(1)
+	case ActionCursorUp, ActionScrollUp:
+		r.scrollDiff(-1)
+	case ActionScrollDownHalf:
+		r.scrollDiff(viewport.R / 2)
+	case ActionScrollUpHalf:
+		r.scrollDiff(-(viewport.R / 2))
+	case ActionBeginning:
+		r.diffOffset = 0
+	case ActionEnd:
+		r.scrollDiff(r.numDiffRows())
+	}
+
+	return r, nil
+}
+
+func (r *Replay) numDiffRows() int {
+	if r.isDiffSplit {
+		return len(diff.Split(r.diff))
+	}
+	return len(r.diff)
+}
+
+func (r *Replay) scrollDiff(delta int) {
+	maxOffset := geom.Max(r.numDiffRows()-r.viewport.R, 0)
+	r.diffOffset = geom.Clamp(r.diffOffset+delta, 0, maxOffset)
+}
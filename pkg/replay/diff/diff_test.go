@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// apply reconstructs both sides of the diff.
+func apply(lines []Line) (a, b []string) {
+	for _, line := range lines {
+		switch line.Op {
+		case OpEqual:
+			a = append(a, line.Text)
+			b = append(b, line.Text)
+		case OpDelete:
+			a = append(a, line.Text)
+		case OpInsert:
+			b = append(b, line.Text)
+		}
+	}
+	return
+}
+
+func TestLines(t *testing.T) {
+	require.Equal(t, 0, len(Lines(nil, nil)))
+	require.NotNil(t, Lines(nil, nil))
+
+	lines := Lines(
+		[]string{"foo", "bar", "baz"},
+		[]string{"foo", "qux", "baz", "quux"},
+	)
+	require.Equal(t, []Line{
+		{Op: OpEqual, Text: "foo"},
+		{Op: OpDelete, Text: "bar"},
+		{Op: OpInsert, Text: "qux"},
+		{Op: OpEqual, Text: "baz"},
+		{Op: OpInsert, Text: "quux"},
+	}, lines)
+	require.Equal(t, " foo\n-bar\n+qux\n baz\n+quux\n", String(lines))
+	require.False(t, IsEqual(lines))
+
+	same := []string{"foo", "bar"}
+	require.True(t, IsEqual(Lines(same, same)))
+}
+
+func TestRandom(t *testing.T) {
+	alphabet := []string{"a", "b", "c"}
+	random := func() (lines []string) {
+		n := rand.Intn(10)
+		for i := 0; i < n; i++ {
+			lines = append(lines, alphabet[rand.Intn(len(alphabet))])
+		}
+		return
+	}
+
+	for i := 0; i < 100; i++ {
+		a, b := random(), random()
+		gotA, gotB := apply(Lines(a, b))
+		require.Equal(t, a, gotA)
+		require.Equal(t, b, gotB)
+	}
+}
+
+func TestLarge(t *testing.T) {
+	const n = 5000
+
+	// No lines in common means n*2 edits, which is the worst case for
+	// memory usage
+	var a, b []string
+	for i := 0; i < n; i++ {
+		a = append(a, fmt.Sprintf("a%d", i))
+		b = append(b, fmt.Sprintf("b%d", i))
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	lines := Lines(a, b)
+	runtime.ReadMemStats(&after)
+
+	gotA, gotB := apply(lines)
+	require.Equal(t, a, gotA)
+	require.Equal(t, b, gotB)
+	require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(16<<20))
+
+	// A few changes in a large input
+	b = append([]string(nil), a...)
+	b[10] = "foo"
+	b = append(b[:n/2], b[n/2+1:]...)
+	b = append(b, "bar")
+
+	lines = Lines(a, b)
+	gotA, gotB = apply(lines)
+	require.Equal(t, a, gotA)
+	require.Equal(t, b, gotB)
+
+	var edits int
+	for _, line := range lines {
+		if line.Op != OpEqual {
+			edits++
+		}
+	}
+	require.Equal(t, 4, edits)
+}
+
+func TestSplit(t *testing.T) {
+	rows := Split(Lines(
+		[]string{"foo", "bar", "baz"},
+		[]string{"foo", "qux", "quux", "baz"},
+	))
+	require.Equal(t, []Row{
+		{Left: "foo", Right: "foo", HasLeft: true, HasRight: true},
+		{Left: "bar", Right: "qux", HasLeft: true, HasRight: true, IsChanged: true},
+		{Right: "quux", HasRight: true, IsChanged: true},
+		{Left: "baz", Right: "baz", HasLeft: true, HasRight: true},
+	}, rows)
+}
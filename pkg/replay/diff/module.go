@@ -0,0 +1,229 @@
+// Package diff compares two sequences of lines of text.
+package diff
+
+import (
+	"strings"
+)
+
+type Op int
+
+const (
+	// The line appears in both sequences
+	OpEqual Op = iota
+	// The line only appears in the second sequence
+	OpInsert
+	// The line only appears in the first sequence
+	OpDelete
+)
+
+// A Line is a single line in the output of a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns the shortest sequence of edits that turns `a` into `b`, using
+// the linear space variant of Myers' algorithm. The result is never nil, even
+// when both `a` and `b` are empty.
+func Lines(a, b []string) (lines []Line) {
+	lines = []Line{}
+	if len(a)+len(b) == 0 {
+		return
+	}
+
+	// Diagonals searched backward are centered on len(a)-len(b), so they
+	// can be up to twice as far from zero as the ones searched forward
+	offset := 2*(len(a)+len(b)) + 2
+	vf := make([]int, 2*offset+1)
+	vb := make([]int, 2*offset+1)
+	return compare(a, b, vf, vb, lines)
+}
+
+// compare appends the edits that turn `a` into `b` to `lines`. `vf` and `vb`
+// are scratch space for middleSnake that is shared by every call, which keeps
+// the memory used by Lines linear in the size of its input.
+func compare(a, b []string, vf, vb []int, lines []Line) []Line {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		lines = append(lines, Line{Op: OpEqual, Text: a[0]})
+		a, b = a[1:], b[1:]
+	}
+
+	var suffix int
+	for suffix < len(a) && suffix < len(b) &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, text := range b {
+			lines = append(lines, Line{Op: OpInsert, Text: text})
+		}
+	case len(b) == 0:
+		for _, text := range a {
+			lines = append(lines, Line{Op: OpDelete, Text: text})
+		}
+	default:
+		// Since `a` and `b` share no prefix or suffix and neither is
+		// empty, at least two edits separate them, so both halves are
+		// strictly smaller problems
+		x, y, u, v := middleSnake(a, b, vf, vb)
+		lines = compare(a[:x], b[:y], vf, vb, lines)
+		for _, text := range a[x:u] {
+			lines = append(lines, Line{Op: OpEqual, Text: text})
+		}
+		lines = compare(a[u:], b[v:], vf, vb, lines)
+	}
+
+	for _, text := range common {
+		lines = append(lines, Line{Op: OpEqual, Text: text})
+	}
+
+	return lines
+}
+
+// middleSnake finds the snake (a run of equal lines) from (x, y) to (u, v)
+// in the middle of a shortest edit path from `a` to `b` by searching forward
+// from the start and backward from the end at the same time.
+func middleSnake(a, b []string, vf, vb []int) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	isOdd := delta%2 != 0
+
+	// vf[k+offset] contains the furthest x reached on diagonal k searching
+	// forward and vb[k+offset] the smallest x reached searching backward
+	offset := (len(vf) - 1) / 2
+	vf[1+offset] = 0
+	vb[delta+1+offset] = n + 1
+
+	for d := 0; d <= (n+m+1)/2; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && vf[k-1+offset] < vf[k+1+offset]) {
+				x = vf[k+1+offset]
+			} else {
+				x = vf[k-1+offset] + 1
+			}
+			y = x - k
+
+			u, v = x, y
+			for u < n && v < m && a[u] == b[v] {
+				u++
+				v++
+			}
+			vf[k+offset] = u
+
+			if isOdd && k >= delta-(d-1) && k <= delta+(d-1) &&
+				u >= vb[k+offset] {
+				return
+			}
+		}
+
+		for k := delta + d; k >= delta-d; k -= 2 {
+			if k == delta-d ||
+				(k != delta+d && vb[k+1+offset]-1 < vb[k-1+offset]) {
+				u = vb[k+1+offset] - 1
+			} else {
+				u = vb[k-1+offset]
+			}
+			v = u - k
+
+			x, y = u, v
+			for x > 0 && y > 0 && a[x-1] == b[y-1] {
+				x--
+				y--
+			}
+			vb[k+offset] = x
+
+			if !isOdd && k >= -d && k <= d && x <= vf[k+offset] {
+				return
+			}
+		}
+	}
+
+	// Unreachable, since the searches always meet by the time they have
+	// each made half of the edits, but deleting all of `a` and inserting
+	// all of `b` is still correct
+	return n, 0, n, 0
+}
+
+// IsEqual reports whether the diff contains no changes.
+func IsEqual(lines []Line) bool {
+	for _, line := range lines {
+		if line.Op != OpEqual {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the diff in the style of `diff -u`, without hunk headers:
+// every line is prefixed with "+", "-", or " ".
+func String(lines []Line) string {
+	var b strings.Builder
+	for _, line := range lines {
+		switch line.Op {
+		case OpEqual:
+			b.WriteString(" ")
+		case OpInsert:
+			b.WriteString("+")
+		case OpDelete:
+			b.WriteString("-")
+		}
+		b.WriteString(line.Text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// A Row is a single row of a side-by-side diff. Either side may be missing if
+// the line was only present in one of the sequences.
+type Row struct {
+	Left, Right string
+	HasLeft     bool
+	HasRight    bool
+	IsChanged   bool
+}
+
+// Split pairs up the lines of a diff so they can be shown side by side. In
+// each run of changed lines, the deleted lines are shown next to the inserted
+// ones.
+func Split(lines []Line) (rows []Row) {
+	for i := 0; i < len(lines); {
+		if lines[i].Op == OpEqual {
+			rows = append(rows, Row{
+				Left:     lines[i].Text,
+				Right:    lines[i].Text,
+				HasLeft:  true,
+				HasRight: true,
+			})
+			i++
+			continue
+		}
+
+		var deleted, inserted []string
+		for ; i < len(lines) && lines[i].Op != OpEqual; i++ {
+			if lines[i].Op == OpDelete {
+				deleted = append(deleted, lines[i].Text)
+				continue
+			}
+			inserted = append(inserted, lines[i].Text)
+		}
+
+		for j := 0; j < len(deleted) || j < len(inserted); j++ {
+			row := Row{IsChanged: true}
+			if j < len(deleted) {
+				row.Left = deleted[j]
+				row.HasLeft = true
+			}
+			if j < len(inserted) {
+				row.Right = inserted[j]
+				row.HasRight = true
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,475 @@
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/cfoust/cy/pkg/emu"
+	"github.com/cfoust/cy/pkg/geom"
+	P "github.com/cfoust/cy/pkg/io/protocol"
+	"github.com/cfoust/cy/pkg/replay/player"
+	"github.com/cfoust/cy/pkg/sessions"
+	"github.com/cfoust/cy/pkg/taro"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type ExportFormat int
+
+const (
+	// A plain-text transcript of the output in the range
+	ExportText ExportFormat = iota
+	// A transcript of the output in the range that preserves colors and
+	// text attributes using ANSI escape sequences
+	ExportANSI
+	// A self-contained HTML document showing the screen at the end of
+	// the range
+	ExportHTML
+	// An Asciicast v2 recording that can be played back with asciinema
+	ExportAsciicast
+)
+
+// ParseExportFormat converts the name of a format (such as "html") into an
+// ExportFormat.
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch name {
+	case "text", "txt":
+		return ExportText, nil
+	case "ansi":
+		return ExportANSI, nil
+	case "html":
+		return ExportHTML, nil
+	case "asciicast", "cast":
+		return ExportAsciicast, nil
+	}
+
+	return 0, fmt.Errorf(
+		"invalid export format %s, must be one of text, ansi, html, or asciicast",
+		name,
+	)
+}
+
+// trimLines removes trailing whitespace from each line and omits empty lines
+// at the end.
+func trimLines(lines []emu.Line) (trimmed []emu.Line) {
+	for _, line := range lines {
+		trimmed = append(
+			trimmed,
+			line[:geom.Min(line.Length(), len(line))],
+		)
+	}
+
+	for len(trimmed) > 0 && len(trimmed[len(trimmed)-1]) == 0 {
+		trimmed = trimmed[:len(trimmed)-1]
+	}
+
+	return
+}
+
+// renderOutput interprets `data` as it would appear on a terminal with
+// `width` columns and returns every line that it produced, including those
+// that scrolled off the top of the screen.
+func renderOutput(data []byte, width int) []emu.Line {
+	width = geom.Max(width, 1)
+	terminal := emu.New(emu.WithSize(geom.Vec2{
+		R: bytes.Count(data, []byte("\n")) + len(data)/width + 1,
+		C: width,
+	}))
+	terminal.Write([]byte(emu.LineFeedMode))
+	terminal.Write(data)
+	return trimLines(terminal.Screen())
+}
+
+// sgr returns the parameters of the SGR escape sequence that sets the colors
+// and attributes of `glyph`.
+func sgr(glyph emu.Glyph) (params []string) {
+	mode := glyph.Mode
+	for _, attr := range []struct {
+		mode  int16
+		param string
+	}{
+		{emu.AttrBold, "1"},
+		{emu.AttrItalic, "3"},
+		{emu.AttrUnderline, "4"},
+		{emu.AttrBlink, "5"},
+		{emu.AttrReverse, "7"},
+		{emu.AttrStrikethrough, "9"},
+	} {
+		if mode&attr.mode != 0 {
+			params = append(params, attr.param)
+		}
+	}
+
+	for _, color := range []struct {
+		color emu.Color
+		isBg  bool
+	}{
+		{glyph.FG, false},
+		{glyph.BG, true},
+	} {
+		base := 30
+		if color.isBg {
+			base = 40
+		}
+
+		if r, g, b, ok := color.color.RGB(); ok {
+			params = append(params, fmt.Sprintf(
+				"%d;2;%d;%d;%d",
+				base+8, r, g, b,
+			))
+		} else if ansi, ok := color.color.ANSI(); ok {
+			if ansi < 8 {
+				params = append(params, fmt.Sprint(base+ansi))
+			} else {
+				params = append(params, fmt.Sprint(base+60+ansi-8))
+			}
+		} else if xterm, ok := color.color.XTerm(); ok {
+			params = append(params, fmt.Sprintf("%d;5;%d", base+8, xterm))
+		}
+	}
+
+	return
+}
+
+// isSameStyle reports whether two glyphs have the same colors and attributes.
+func isSameStyle(a, b emu.Glyph) bool {
+	return a.Mode == b.Mode && a.FG == b.FG && a.BG == b.BG
+}
+
+// writeANSI writes `lines` to `w` using SGR escape sequences for colors and
+// text attributes. Lines are separated by `newline`.
+func writeANSI(w *bytes.Buffer, lines []emu.Line, newline string) {
+	for i, line := range lines {
+		if i > 0 {
+			w.WriteString(newline)
+		}
+
+		var last emu.Glyph
+		isStyled := false
+		for col := 0; col < len(line); col++ {
+			glyph := line[col]
+			if col == 0 || !isSameStyle(glyph, last) {
+				params := sgr(glyph)
+				if isStyled || len(params) > 0 {
+					fmt.Fprintf(w, "\x1b[0;%sm", strings.Join(params, ";"))
+				}
+				isStyled = len(params) > 0
+				last = glyph
+			}
+
+			w.WriteRune(glyph.Char)
+			col += glyph.Width() - 1
+		}
+
+		if isStyled {
+			w.WriteString("\x1b[0m")
+		}
+	}
+}
+
+const (
+	htmlForeground = "#e5e5e5"
+	htmlBackground = "#000000"
+)
+
+var ansiPalette = []string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00",
+	"#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00",
+	"#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// colorHex converts `color` into a CSS color using xterm's default palette.
+// `ok` is false if `color` is the default color.
+func colorHex(color emu.Color) (hex string, ok bool) {
+	if r, g, b, ok := color.RGB(); ok {
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b), true
+	}
+
+	xterm, ok := color.XTerm()
+	if !ok {
+		return
+	}
+
+	switch {
+	case xterm < 16:
+		return ansiPalette[xterm], true
+	case xterm < 232:
+		levels := []int{0, 95, 135, 175, 215, 255}
+		xterm -= 16
+		return fmt.Sprintf(
+			"#%02x%02x%02x",
+			levels[xterm/36],
+			levels[(xterm/6)%6],
+			levels[xterm%6],
+		), true
+	default:
+		gray := 8 + (xterm-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray), true
+	}
+}
+
+// htmlStyle returns the inline CSS for `glyph`.
+func htmlStyle(glyph emu.Glyph) string {
+	fg, hasFG := colorHex(glyph.FG)
+	bg, hasBG := colorHex(glyph.BG)
+
+	if glyph.Mode&emu.AttrReverse != 0 {
+		if !hasFG {
+			fg = htmlForeground
+		}
+		if !hasBG {
+			bg = htmlBackground
+		}
+		fg, bg = bg, fg
+		hasFG, hasBG = true, true
+	}
+
+	var styles []string
+	if hasFG {
+		styles = append(styles, "color:"+fg)
+	}
+	if hasBG {
+		styles = append(styles, "background-color:"+bg)
+	}
+	if glyph.Mode&emu.AttrBold != 0 {
+		styles = append(styles, "font-weight:bold")
+	}
+	if glyph.Mode&emu.AttrItalic != 0 {
+		styles = append(styles, "font-style:italic")
+	}
+
+	var decorations []string
+	if glyph.Mode&emu.AttrUnderline != 0 {
+		decorations = append(decorations, "underline")
+	}
+	if glyph.Mode&emu.AttrStrikethrough != 0 {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		styles = append(
+			styles,
+			"text-decoration:"+strings.Join(decorations, " "),
+		)
+	}
+
+	return strings.Join(styles, ";")
+}
+
+// writeHTML writes a self-contained HTML document showing `lines` to `w`.
+func writeHTML(w *bytes.Buffer, lines []emu.Line) {
+	fmt.Fprintf(
+		w,
+		`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>cy</title>
+</head>
+<body style="margin:0;background-color:%s">
+<pre style="margin:0;padding:1em;font-family:monospace;color:%s;background-color:%s">`,
+		htmlBackground,
+		htmlForeground,
+		htmlBackground,
+	)
+
+	for i, line := range lines {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+
+		for col := 0; col < len(line); {
+			style := htmlStyle(line[col])
+
+			var text strings.Builder
+			for ; col < len(line) && htmlStyle(line[col]) == style; col++ {
+				text.WriteRune(line[col].Char)
+				col += line[col].Width() - 1
+			}
+
+			if len(style) == 0 {
+				w.WriteString(html.EscapeString(text.String()))
+				continue
+			}
+
+			fmt.Fprintf(
+				w,
+				`<span style="%s">%s</span>`,
+				style,
+				html.EscapeString(text.String()),
+			)
+		}
+	}
+
+	w.WriteString("</pre>\n</body>\n</html>\n")
+}
+
+// screenEvent returns an OutputMessage that, when written to an empty
+// terminal, reproduces the screen of `terminal`.
+func screenEvent(terminal emu.Terminal) P.OutputMessage {
+	var data bytes.Buffer
+	if terminal.IsAltMode() {
+		data.WriteString("\x1b[?1049h")
+	}
+	data.WriteString("\x1b[H\x1b[2J")
+	writeANSI(&data, trimLines(terminal.Screen()), "\r\n")
+
+	cursor := terminal.Cursor()
+	fmt.Fprintf(&data, "\x1b[%d;%dH", cursor.R+1, cursor.C+1)
+	return P.OutputMessage{Data: data.Bytes()}
+}
+
+// Export writes the events in `events` that occurred after the event at
+// index `from`, up to and including the event at index `to`, to `w` in the
+// given format. If `from` is -1, the range begins before the first event.
+func Export(
+	w io.Writer,
+	format ExportFormat,
+	events []sessions.Event,
+	from, to int,
+) error {
+	if from < -1 || to < 0 || to >= len(events) || from > to {
+		return fmt.Errorf("invalid range [%d, %d]", from, to)
+	}
+
+	end := player.At(events, to, -1)
+	width := end.Size().C
+
+	var data []byte
+	for _, event := range events[from+1 : to+1] {
+		if output, ok := event.Message.(P.OutputMessage); ok {
+			data = append(data, output.Data...)
+		}
+	}
+
+	var b bytes.Buffer
+	switch format {
+	case ExportText:
+		for _, line := range renderOutput(data, width) {
+			b.WriteString(line.String())
+			b.WriteString("\n")
+		}
+	case ExportANSI:
+		lines := renderOutput(data, width)
+		writeANSI(&b, lines, "\n")
+		if len(lines) > 0 {
+			b.WriteString("\n")
+		}
+	case ExportHTML:
+		writeHTML(&b, trimLines(end.Screen()))
+	case ExportAsciicast:
+		// The recording begins with the state of the screen at
+		// `from`, or with a blank screen if it begins before the first
+		// event
+		start := player.At(events, geom.Max(from, 0), -1)
+		size := start.Size()
+		stamp := events[geom.Max(from, 0)].Stamp
+
+		clip := []sessions.Event{
+			{
+				Stamp: stamp,
+				Message: P.SizeMessage{
+					Rows:    size.R,
+					Columns: size.C,
+				},
+			},
+		}
+		if from >= 0 {
+			clip = append(clip, sessions.Event{
+				Stamp:   stamp,
+				Message: screenEvent(start),
+			})
+		}
+		clip = append(clip, events[from+1:to+1]...)
+
+		return sessions.WriteAsciicast(w, clip)
+	default:
+		return fmt.Errorf("unknown export format")
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// exportRange determines which events should be exported. If a mark is set,
+// the range spans from the mark to the current location. Otherwise it covers
+// the detected command whose output contains the cursor (or the start of the
+// selection) in copy mode, falling back to the last command that was
+// executed before the current location.
+func (r *Replay) exportRange() (from, to int) {
+	location := r.Location().Index
+	if r.mark != nil {
+		from, to = r.mark.Index, location
+		if from > to {
+			from, to = to, from
+		}
+		return
+	}
+
+	commands := r.Commands()
+	if r.isCopyMode() && r.isFlowMode() {
+		cursor := r.movement.Cursor()
+		if r.isSelecting {
+			cursor = r.selectStart
+		}
+
+		for _, command := range commands {
+			output := command.Output
+			if command.Pending || cursor.LT(output.From) || cursor.GT(output.To) {
+				continue
+			}
+
+			return command.Executed, command.Completed
+		}
+	}
+
+	for i := len(commands) - 1; i >= 0; i-- {
+		command := commands[i]
+		if command.Pending || command.Executed >= location {
+			continue
+		}
+
+		return command.Executed, geom.Min(command.Completed, location)
+	}
+
+	return 0, location
+}
+
+// exportError reports an error that occurred while exporting.
+func exportError(err error) tea.Msg {
+	return taro.PublishMsg{
+		Msg: ErrorEvent{
+			Err: fmt.Errorf("failed to export: %w", err),
+		},
+	}
+}
+
+// export exports a range of events (see exportRange) in the format named
+// `name` and copies the result.
+func (r *Replay) export(name string) tea.Cmd {
+	format, err := ParseExportFormat(name)
+	if err != nil {
+		return func() tea.Msg {
+			return exportError(err)
+		}
+	}
+
+	events := r.Events()
+	from, to := r.exportRange()
+	r.isSelecting = false
+	return func() tea.Msg {
+		var b bytes.Buffer
+		if err := Export(&b, format, events, from, to); err != nil {
+			return exportError(err)
+		}
+
+		return taro.PublishMsg{
+			Msg: CopyEvent{
+				Text: b.String(),
+			},
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package replay
+
+import (
+	"bytes"
+
+	P "github.com/cfoust/cy/pkg/io/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// outputEvent is sent to Replay when the underlying pane produced new
+// events while replay mode was open.
+type outputEvent struct{}
+
+// isAtTail reports whether the replay is showing the most recent event in its
+// history.
+func (r *Replay) isAtTail() bool {
+	return r.Location().Index >= len(r.Events())-1
+}
+
+// isAttached reports whether new output should be shown immediately, which is
+// only true while following and viewing the end of the pane's history in time
+// mode.
+func (r *Replay) isAttached() bool {
+	return r.isFollowing &&
+		r.mode == ModeTime &&
+		!r.isPlaying &&
+		r.isAtTail()
+}
+
+// handleOutput consumes events the pane produced while replay mode was open.
+// If the user is attached to the tail, the replay moves to the newest event;
+// otherwise we just keep track of how much output they have missed.
+func (r *Replay) handleOutput() tea.Cmd {
+	if !r.isFollowing {
+		return nil
+	}
+
+	isAttached := r.isAttached()
+
+	events := r.Flush()
+	if len(events) == 0 {
+		return nil
+	}
+
+	if isAttached && !r.isSeeking {
+		return r.setIndex(-1, -1, true)
+	}
+
+	for _, event := range events {
+		if output, ok := event.Message.(P.OutputMessage); ok {
+			r.newLines += bytes.Count(output.Data, []byte("\n"))
+		}
+	}
+
+	return nil
+}
+
+// follow enables follow mode and jumps to the end of the pane's history.
+func (r *Replay) follow() tea.Cmd {
+	r.isFollowing = true
+	r.Flush()
+	r.newLines = 0
+
+	if r.isCopyMode() {
+		r.exitCopyMode()
+	}
+
+	if r.isAtTail() {
+		return nil
+	}
+
+	return r.gotoIndex(-1, -1)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/cfoust/cy/pkg/bind"
 	"github.com/cfoust/cy/pkg/geom"
+	"github.com/cfoust/cy/pkg/replay/diff"
 	"github.com/cfoust/cy/pkg/replay/motion"
 	"github.com/cfoust/cy/pkg/replay/movement"
 	"github.com/cfoust/cy/pkg/replay/player"
@@ -51,6 +52,22 @@ type Replay struct {
 	playbackRate int
 	currentTime  time.Time
 
+	// Whether new output from the pane should be shown as it arrives
+	// (like `less +F`) as long as the user is at the end of its history
+	isFollowing bool
+	// The number of lines of output that arrived while the user was not
+	// at the end of the pane's history
+	newLines int
+
+	// A location in time set by the user that is used for comparing the
+	// screen at two different points in time
+	mark *search.Address
+	// The differences between the screen at the mark and the current
+	// location. The diff is shown if this is not nil.
+	diff        []diff.Line
+	isDiffSplit bool
+	diffOffset  int
+
 	movement movement.Movement
 
 	// Whether moving in time should skip inactivity
@@ -64,7 +81,11 @@ type Replay struct {
 	isForward bool
 	isWaiting bool
 	// Whether no matches came back
-	isEmpty                bool
+	isEmpty bool
+	// Whether the input prompt is for a time rather than a search
+	isTimeInput bool
+	// Whether the last time the user entered could not be parsed
+	isInvalidTime          bool
 	searchProgress         chan int
 	progressPercent        int
 	searchInput, incrInput textinput.Model
@@ -158,6 +179,12 @@ func WithCopyMode(r *Replay) {
 	r.enterCopyMode()
 }
 
+// WithFollow puts Replay into follow mode, which shows new output as it
+// arrives.
+func WithFollow(r *Replay) {
+	r.isFollowing = true
+}
+
 // WithFlow swaps to flow mode, if possible.
 func WithFlow(r *Replay) {
 	if r.isFlowMode() {
@@ -65,8 +65,8 @@ func findLine(re *regexp.Regexp, line emu.Line) (loc []int) {
 	return
 }
 
-// findAllLine returns all matches of `re` in `line`.
-func findAllLine(re *regexp.Regexp, line emu.Line) (loc [][]int) {
+// FindAllLine returns all matches of `re` in `line`.
+func FindAllLine(re *regexp.Regexp, line emu.Line) (loc [][]int) {
 	var i int
 	for i < len(line) {
 		match := findLine(re, line[i:])
@@ -189,7 +189,7 @@ func findNext(
 				from,
 				line,
 				row,
-				findAllLine(re, line),
+				FindAllLine(re, line),
 			)
 			if ok {
 				return
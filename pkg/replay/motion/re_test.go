@@ -32,7 +32,7 @@ func TestFind(t *testing.T) {
 			{4, 7},
 			{8, 11},
 		},
-		findAllLine(
+		FindAllLine(
 			makePattern("foo"),
 			emu.LineFromString("foo foo foo"),
 		),
@@ -35,7 +35,7 @@ func getLineMatches(
 		return
 	}
 
-	return findAllLine(re, line), true
+	return FindAllLine(re, line), true
 }
 
 func nextWord(
@@ -101,7 +101,7 @@ func nextWord(
 			}, true
 		}
 
-		matches := findAllLine(re, line)
+		matches := FindAllLine(re, line)
 
 		// Non-blank lines with no words are skipped
 		if len(matches) == 0 {
@@ -53,13 +53,34 @@ func (p *Player) Release() {
 	p.inUse = false
 	buffer := p.buffer
 	p.buffer = make([]sessions.Event, 0)
+	numEvents := len(p.events)
+	isBehind := p.location.Index < numEvents-1
 	p.mu.Unlock()
 
+	// Events may have been added with Flush while the Player was in use,
+	// so we might not be at the end
+	if isBehind {
+		p.Goto(numEvents-1, -1)
+	}
+
 	for _, event := range buffer {
 		p.consume(event)
 	}
 }
 
+// Flush adds all of the events that arrived while the Player was in use to
+// its history without changing its location. It returns the events that were
+// added.
+func (p *Player) Flush() []sessions.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	buffer := p.buffer
+	p.buffer = make([]sessions.Event, 0)
+	p.events = append(p.events, buffer...)
+	return buffer
+}
+
 // Output gets all of the output written in the range [start, end).
 func (p *Player) Output(start, end int) (data []byte, ok bool) {
 	p.mu.RLock()
@@ -171,6 +192,15 @@ func New() *Player {
 	return p
 }
 
+// At returns a new Player that contains `events` and has been moved to the
+// event at `index` and byte offset `offset` (see Goto).
+func At(events []sessions.Event, index, offset int) *Player {
+	player := New()
+	player.events = events
+	player.Goto(index, offset)
+	return player
+}
+
 func FromEvents(events []sessions.Event) *Player {
 	player := New()
 
@@ -47,3 +47,25 @@ func TestTime(t *testing.T) {
 	require.Equal(t, p.nextDetect, 7)
 	require.Equal(t, "foobar", getLine(p, 0))
 }
+
+func TestFlush(t *testing.T) {
+	events := sessions.NewSimulator().
+		Defaults().
+		Add("foo").
+		Events()
+
+	p := FromEvents(events)
+	p.Acquire()
+
+	p.Process(sessions.Event{})
+	require.Equal(t, len(events), len(p.Events()))
+
+	flushed := p.Flush()
+	require.Equal(t, 1, len(flushed))
+	require.Equal(t, len(events)+1, len(p.Events()))
+	require.Equal(t, len(events)-1, p.Location().Index)
+	require.Equal(t, 0, len(p.Flush()))
+
+	p.Release()
+	require.Equal(t, len(events), p.Location().Index)
+}
@@ -1,13 +1,17 @@
 package replay
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/cfoust/cy/pkg/bind"
 	"github.com/cfoust/cy/pkg/emu"
 	"github.com/cfoust/cy/pkg/geom"
+	P "github.com/cfoust/cy/pkg/io/protocol"
 	"github.com/cfoust/cy/pkg/replay/detect"
+	"github.com/cfoust/cy/pkg/replay/diff"
 	"github.com/cfoust/cy/pkg/replay/player"
 	"github.com/cfoust/cy/pkg/sessions"
 	"github.com/cfoust/cy/pkg/taro"
@@ -197,6 +201,210 @@ func TestTimeJump(t *testing.T) {
 	require.Equal(t, 0, r.Location().Index)
 }
 
+func TestParseTimeTarget(t *testing.T) {
+	target, err := ParseTimeTarget("-5m")
+	require.NoError(t, err)
+	require.True(t, target.IsRelative)
+	require.Equal(t, -5*time.Minute, target.Delta)
+
+	target, err = ParseTimeTarget("+1h30s")
+	require.NoError(t, err)
+	require.Equal(t, time.Hour+30*time.Second, target.Delta)
+
+	target, err = ParseTimeTarget("14:32")
+	require.NoError(t, err)
+	require.True(t, target.IsClock)
+
+	reference := time.Date(2026, 10, 15, 8, 0, 0, 0, time.Local)
+	require.Equal(
+		t,
+		time.Date(2026, 10, 15, 14, 32, 0, 0, time.Local),
+		target.Resolve(reference),
+	)
+
+	target, err = ParseTimeTarget("2026-10-15 09:00")
+	require.NoError(t, err)
+	require.False(t, target.IsClock)
+	require.Equal(
+		t,
+		time.Date(2026, 10, 15, 9, 0, 0, 0, time.Local),
+		target.Resolve(reference),
+	)
+
+	_, err = ParseTimeTarget("2026-10-15T09:00:00Z")
+	require.NoError(t, err)
+
+	for _, value := range []string{"", "-", "foo", "25:00"} {
+		_, err = ParseTimeTarget(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestGotoTime(t *testing.T) {
+	size := geom.Size{R: 5, C: 10}
+	e := sim().
+		Add(size).
+		AddTime(0, "test").
+		AddTime(3*time.Minute, "test").
+		AddTime(60*time.Minute, "test").
+		Events()
+
+	r, i := createTest(e)
+	i(size, ActionBeginning, ActionGotoTime, "+5m", "enter")
+	require.Equal(t, 5*time.Minute, r.currentTime.Sub(e[0].Stamp))
+	require.Equal(t, 2, r.Location().Index)
+
+	i(ActionGotoTime, "-4m", "enter")
+	require.Equal(t, time.Minute, r.currentTime.Sub(e[0].Stamp))
+	require.Equal(t, 1, r.Location().Index)
+
+	// Absolute times
+	stamp := e[len(e)-1].Stamp.Add(-time.Second)
+	i(ActionGotoTime, stamp.Format("2006-01-02 15:04:05"), "enter")
+	require.Equal(t, len(e)-2, r.Location().Index)
+
+	// Clamped to the end
+	i(ActionEvent{Type: ActionGotoTime, Arg: "+1d"})
+	require.Equal(t, len(e)-1, r.Location().Index)
+
+	i(ActionGotoTime, "blah", "enter")
+	require.True(t, r.isInvalidTime)
+	require.False(t, r.isTimeInput)
+	require.Equal(t, ModeTime, r.mode)
+
+	// The prompt leaves copy mode just like providing Arg does
+	i(ActionCursorUp, ActionSelect)
+	require.True(t, r.isSelecting)
+	i(ActionGotoTime, "-1m", "enter")
+	require.False(t, r.isSelecting)
+	require.False(t, r.isTimeInput)
+	require.Equal(t, ModeTime, r.mode)
+}
+
+func TestFollow(t *testing.T) {
+	size := geom.Size{R: 5, C: 10}
+	e := sim().
+		Add(size, "test").
+		Events()
+
+	r, i := createTest(e)
+	r.Acquire()
+	i(size, ActionFollow)
+	require.True(t, r.isFollowing)
+	require.True(t, r.isAttached())
+
+	output := func(data string) {
+		r.Process(sessions.Event{
+			Stamp:   time.Now(),
+			Message: P.OutputMessage{Data: []byte(data)},
+		})
+		i(outputEvent{})
+	}
+
+	// New output is consumed while at the end
+	output("\nfoo")
+	require.Equal(t, len(e), r.Location().Index)
+	require.Equal(t, 0, r.newLines)
+
+	// Going back in time detaches from the end
+	i(ActionBeginning)
+	require.False(t, r.isAttached())
+	output("\nbar\nbaz")
+	require.Equal(t, 0, r.Location().Index)
+	require.Equal(t, len(e)+2, len(r.Events()))
+	require.Equal(t, 2, r.newLines)
+
+	// Returning to the end attaches again
+	i(ActionEnd)
+	require.True(t, r.isAttached())
+	require.Equal(t, 0, r.newLines)
+
+	// Leaving copy mode returns to the end
+	i(ActionCursorUp)
+	require.Equal(t, ModeCopy, r.mode)
+	output("\nqux")
+	require.Equal(t, 1, r.newLines)
+	i(ActionQuit)
+	require.Equal(t, ModeTime, r.mode)
+	require.True(t, r.isAttached())
+	require.Equal(t, 0, r.newLines)
+
+	// Follow mode can be turned off
+	i(ActionFollow)
+	require.False(t, r.isFollowing)
+	output("\nquux")
+	require.Equal(t, len(r.Events())-1, r.Location().Index)
+}
+
+func TestDiff(t *testing.T) {
+	size := geom.Size{R: 5, C: 10}
+	e := sim().
+		Add(
+			size,
+			emu.LineFeedMode,
+			"foo\nbar\n",
+		).
+		Term(terminfo.ClearScreen).
+		Add("foo\nbaz\n").
+		Events()
+
+	require.Equal(t, []string{"foo", "bar"}, ScreenAt(e, 2, -1))
+
+	r, i := createTest(e)
+	i(size)
+
+	// Nothing happens without a mark
+	i(ActionDiff)
+	require.Nil(t, r.diff)
+
+	i(ActionBeginning)
+	r.forceIndex(2, -1)
+	i(ActionMark, ActionEnd, ActionDiff)
+	require.Equal(t, []diff.Line{
+		{Op: diff.OpEqual, Text: "foo"},
+		{Op: diff.OpDelete, Text: "bar"},
+		{Op: diff.OpInsert, Text: "baz"},
+	}, r.diff)
+
+	i(ActionDiff)
+	require.True(t, r.isDiffSplit)
+
+	i(ActionQuit)
+	require.Nil(t, r.diff)
+	require.Equal(t, ModeTime, r.mode)
+}
+
+func TestDiffBlank(t *testing.T) {
+	size := geom.Size{R: 5, C: 10}
+	e := sim().
+		Add(size, "\n").
+		Events()
+
+	r, i := createTest(e)
+	i(size)
+
+	// Two blank screens still open the diff view
+	i(ActionBeginning, ActionMark, ActionEnd, ActionDiff)
+	require.NotNil(t, r.diff)
+	require.Equal(t, 0, len(r.diff))
+
+	i(ActionQuit)
+	require.Nil(t, r.diff)
+}
+
+func TestOutputLines(t *testing.T) {
+	require.Equal(
+		t,
+		[]string{"foo", "bar", "", "baz"},
+		OutputLines([]byte("foo\r\nbar\n\nbaz\n"), 10),
+	)
+	require.Equal(
+		t,
+		[]string{"foob", "ar"},
+		OutputLines([]byte("foobar"), 4),
+	)
+}
+
 func TestPrompt(t *testing.T) {
 	r, i := createTest(createTestSession())
 	i(geom.DEFAULT_SIZE)
@@ -351,3 +559,56 @@ func TestIncremental(t *testing.T) {
 	i(ActionSearchAgain)
 	require.Equal(t, geom.Vec2{R: 0, C: 0}, r.movement.Cursor())
 }
+
+func TestExport(t *testing.T) {
+	size := geom.Size{R: 5, C: 10}
+	events := sim().
+		Add(
+			size,
+			emu.LineFeedMode,
+			"$ ",
+		).
+		Add("\x1b[31mred\x1b[0m\nplain\n").
+		Events()
+
+	export := func(format ExportFormat) string {
+		var b bytes.Buffer
+		require.NoError(t, Export(&b, format, events, 2, len(events)-1))
+		return b.String()
+	}
+
+	require.Equal(t, "red\nplain\n", export(ExportText))
+	require.Equal(
+		t,
+		"\x1b[0;31mred\x1b[0m\nplain\n",
+		export(ExportANSI),
+	)
+
+	page := export(ExportHTML)
+	require.Contains(t, page, `$ <span style="color:#cd0000">red</span>`)
+	require.Contains(t, page, "plain</pre>")
+
+	cast := export(ExportAsciicast)
+	require.True(t, strings.HasPrefix(
+		cast,
+		`{"height":5,"version":2,"width":10}`,
+	))
+	require.Contains(t, cast, `\u001b[H\u001b[2J$`)
+
+	require.Error(t, Export(&bytes.Buffer{}, ExportText, events, 3, 2))
+
+	// Ranges that begin before the first event include it
+	var b bytes.Buffer
+	require.NoError(t, Export(&b, ExportText, events, -1, len(events)-1))
+	require.Equal(t, "$ red\nplain\n", b.String())
+	require.Error(t, Export(&bytes.Buffer{}, ExportText, nil, -1, -1))
+
+	_, err := ParseExportFormat("pdf")
+	require.Error(t, err)
+
+	// Failures are reported to the user
+	r, _ := createTest(events)
+	publish, ok := r.export("pdf")().(taro.PublishMsg)
+	require.True(t, ok)
+	require.IsType(t, ErrorEvent{}, publish.Msg)
+}
@@ -2,6 +2,8 @@ package replay
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"github.com/cfoust/cy/pkg/bind"
 	"github.com/cfoust/cy/pkg/emu"
@@ -9,7 +11,9 @@ import (
 	"github.com/cfoust/cy/pkg/geom/tty"
 	"github.com/cfoust/cy/pkg/mux"
 	S "github.com/cfoust/cy/pkg/mux/screen"
+	"github.com/cfoust/cy/pkg/params"
 	"github.com/cfoust/cy/pkg/replay/detect"
+	"github.com/cfoust/cy/pkg/replay/diff"
 	"github.com/cfoust/cy/pkg/replay/movement"
 	"github.com/cfoust/cy/pkg/replay/player"
 	"github.com/cfoust/cy/pkg/sessions"
@@ -29,6 +33,7 @@ type Replayable struct {
 	terminal    *S.Terminal
 	replay      *taro.Program
 	player      *player.Player
+	params      *params.Parameters
 
 	timeBinds, copyBinds *bind.BindScope
 }
@@ -39,6 +44,13 @@ func (r *Replayable) Kill() {
 	r.Cancel()
 }
 
+// SetParams implements tree.ParamReader.
+func (r *Replayable) SetParams(params *params.Parameters) {
+	r.Lock()
+	r.params = params
+	r.Unlock()
+}
+
 func (r *Replayable) Stream() mux.Stream {
 	return r.stream
 }
@@ -56,10 +68,147 @@ func (r *Replayable) Commands() []detect.Command {
 	return r.player.Commands()
 }
 
+// Events returns all of the events that have occurred in this Replayable.
+func (r *Replayable) Events() []sessions.Event {
+	return r.player.Events()
+}
+
 func (r *Replayable) Output(start, end int) (data []byte, ok bool) {
 	return r.player.Output(start, end)
 }
 
+// Command gets the command at `index` in the list of commands detected in
+// this pane. Negative indices count back from the most recent command that
+// has finished.
+func (r *Replayable) Command(index int) (command detect.Command, err error) {
+	commands := r.Commands()
+
+	original := index
+
+	// Skip pending command
+	if index < 0 && len(commands) > 0 && commands[len(commands)-1].Pending {
+		index--
+	}
+
+	if index < 0 {
+		index = len(commands) + index
+	}
+
+	if index < 0 || index >= len(commands) {
+		err = fmt.Errorf(
+			"index %d out of range",
+			original,
+		)
+		return
+	}
+
+	return commands[index], nil
+}
+
+// CommandOutput gets the output of the command at `index` (see Command).
+func (r *Replayable) CommandOutput(index int) ([]byte, error) {
+	command, err := r.Command(index)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := r.Output(command.Executed+1, command.Completed+1)
+	if !ok {
+		return nil, fmt.Errorf("no output")
+	}
+
+	// Skip the newline produced when the user originally executed the
+	// command
+	if len(data) > 1 && data[0] == '\r' && data[1] == '\n' {
+		data = data[2:]
+	}
+
+	return data, nil
+}
+
+// DiffCommands compares the output of the commands at indices `a` and `b`
+// (see CommandOutput).
+func (r *Replayable) DiffCommands(a, b int) ([]diff.Line, error) {
+	outputA, err := r.CommandOutput(a)
+	if err != nil {
+		return nil, err
+	}
+
+	outputB, err := r.CommandOutput(b)
+	if err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	width := r.size.C
+	r.RUnlock()
+
+	return diff.Lines(
+		OutputLines(outputA, width),
+		OutputLines(outputB, width),
+	), nil
+}
+
+// DiffScreens compares the screen immediately after the events at indices
+// `a` and `b`. Negative indices count back from the most recent event.
+func (r *Replayable) DiffScreens(a, b int) ([]diff.Line, error) {
+	events := r.player.Events()
+	numEvents := len(events)
+	for _, index := range []*int{&a, &b} {
+		if *index < 0 {
+			*index += numEvents
+		}
+
+		if *index < 0 || *index >= numEvents {
+			return nil, fmt.Errorf("index out of range")
+		}
+	}
+
+	return diff.Lines(
+		ScreenAt(events, a, -1),
+		ScreenAt(events, b, -1),
+	), nil
+}
+
+// Export writes the events after the event at index `from`, up to and
+// including the one at index `to`, to `w` in the given format. Negative
+// indices count back from the most recent event.
+func (r *Replayable) Export(
+	w io.Writer,
+	format ExportFormat,
+	from, to int,
+) error {
+	events := r.player.Events()
+	numEvents := len(events)
+	for _, index := range []*int{&from, &to} {
+		if *index < 0 {
+			*index += numEvents
+		}
+	}
+
+	return Export(w, format, events, from, to)
+}
+
+// ExportFromStart writes every event up to and including the one at index
+// `to` to `w` in the given format. A negative `to` counts back from the most
+// recent event. If there are no events, nothing is written.
+func (r *Replayable) ExportFromStart(
+	w io.Writer,
+	format ExportFormat,
+	to int,
+) error {
+	events := r.player.Events()
+	if len(events) == 0 {
+		return nil
+	}
+
+	if to < 0 {
+		to += len(events)
+	}
+
+	return Export(w, format, events, -1, to)
+}
+
 func (r *Replayable) Preview(
 	location geom.Vec2,
 	highlights []movement.Highlight,
@@ -70,7 +219,8 @@ func (r *Replayable) Preview(
 	return r.player.Preview(size, location, highlights)
 }
 
-func (r *Replayable) isReplayMode() bool {
+// IsReplayMode reports whether the Replayable is in replay mode.
+func (r *Replayable) IsReplayMode() bool {
 	r.RLock()
 	showReplay := r.replay != nil
 	r.RUnlock()
@@ -79,7 +229,7 @@ func (r *Replayable) isReplayMode() bool {
 
 func (r *Replayable) State() *tty.State {
 	var currentScreen mux.Screen = r.terminal
-	if r.isReplayMode() {
+	if r.IsReplayMode() {
 		currentScreen = r.replay
 	}
 
@@ -114,7 +264,13 @@ func (r *Replayable) poll(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case event := <-terminalEvents.Recv():
-			if r.isReplayMode() {
+			r.RLock()
+			replay := r.replay
+			r.RUnlock()
+
+			// Replay decides whether to show new output
+			if replay != nil {
+				replay.Send(outputEvent{})
 				continue
 			}
 			r.Publish(event)
@@ -123,7 +279,7 @@ func (r *Replayable) poll(ctx context.Context) {
 }
 
 func (r *Replayable) Send(msg mux.Msg) {
-	if r.isReplayMode() {
+	if r.IsReplayMode() {
 		r.replay.Send(msg)
 		return
 	}
@@ -133,7 +289,15 @@ func (r *Replayable) Send(msg mux.Msg) {
 	if mouse, ok := msg.(taro.MouseMsg); ok {
 		isMouseUp := mouse.Type == taro.MousePress && mouse.Button == taro.MouseWheelUp
 		if isMouseUp && !r.terminal.IsAltMode() {
-			r.EnterReplay()
+			r.RLock()
+			params := r.params
+			r.RUnlock()
+
+			if params != nil && params.ReplayFollowOnScroll() {
+				r.EnterReplay(WithFollow)
+			} else {
+				r.EnterReplay()
+			}
 			return
 		}
 	}
@@ -185,9 +349,10 @@ func (r *Replayable) EnterReplay(options ...Option) {
 	}()
 }
 
-func NewReplayable(
+func newReplayable(
 	ctx context.Context,
 	cmd, stream mux.Stream,
+	player *player.Player,
 	timeBinds, copyBinds *bind.BindScope,
 ) *Replayable {
 	lifetime := util.NewLifetime(ctx)
@@ -198,7 +363,7 @@ func NewReplayable(
 		copyBinds:       copyBinds,
 		cmd:             cmd,
 		stream:          stream,
-		player:          player.New(),
+		player:          player,
 	}
 	r.terminal = S.NewTerminal(
 		lifetime.Ctx(),
@@ -211,3 +376,37 @@ func NewReplayable(
 
 	return r
 }
+
+func NewReplayable(
+	ctx context.Context,
+	cmd, stream mux.Stream,
+	timeBinds, copyBinds *bind.BindScope,
+) *Replayable {
+	return newReplayable(
+		ctx,
+		cmd,
+		stream,
+		player.New(),
+		timeBinds,
+		copyBinds,
+	)
+}
+
+// NewReplayableFrom creates a Replayable whose history begins with `events`,
+// such as those recorded by a previous process. Only replay mode shows these
+// events; the terminal starts out empty.
+func NewReplayableFrom(
+	ctx context.Context,
+	cmd, stream mux.Stream,
+	events []sessions.Event,
+	timeBinds, copyBinds *bind.BindScope,
+) *Replayable {
+	return newReplayable(
+		ctx,
+		cmd,
+		stream,
+		player.FromEvents(events),
+		timeBinds,
+		copyBinds,
+	)
+}
@@ -140,6 +140,10 @@ func (r *Replay) handleSearchInput(msg tea.Msg) (taro.Model, tea.Cmd) {
 			r.mode = ModeTime
 			r.progressPercent = 0
 
+			if r.isTimeInput {
+				return r, r.submitTime(value)
+			}
+
 			if match := TIME_DELTA_REGEX.FindStringSubmatch(value); match != nil {
 				delta := parseTimeDelta(match)
 				if !r.isForward {
@@ -1,8 +1,10 @@
 package replay
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cfoust/cy/pkg/taro"
@@ -25,6 +27,10 @@ func (r *Replay) handleSeek(updateTime bool) {
 		r.currentTime = events[location.Index].Stamp
 	}
 
+	if r.isAtTail() {
+		r.newLines = 0
+	}
+
 	r.mode = ModeTime
 	r.initializeMovement()
 }
@@ -99,6 +105,137 @@ func parseTimeDelta(delta []string) (result time.Duration) {
 	return
 }
 
+// TimeTarget is a point in time the user wants to seek to. It is either an
+// offset from the current time in the replay, a wall-clock time on the same
+// day as the current time, or a full date and time.
+type TimeTarget struct {
+	// Whether this target is relative to the current time.
+	IsRelative bool
+	Delta      time.Duration
+
+	// Whether Time only contains a time of day. The date is taken from the
+	// current time in the replay.
+	IsClock bool
+	Time    time.Time
+}
+
+// Resolve returns the absolute time that this target refers to, using
+// `current` as the reference point.
+func (t TimeTarget) Resolve(current time.Time) time.Time {
+	if t.IsRelative {
+		return current.Add(t.Delta)
+	}
+
+	if !t.IsClock {
+		return t.Time
+	}
+
+	current = current.Local()
+	year, month, day := current.Date()
+	return time.Date(
+		year,
+		month,
+		day,
+		t.Time.Hour(),
+		t.Time.Minute(),
+		t.Time.Second(),
+		0,
+		time.Local,
+	)
+}
+
+var (
+	CLOCK_FORMATS = []string{
+		"15:04",
+		"15:04:05",
+	}
+	DATE_FORMATS = []string{
+		"2006-01-02 15:04",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02T15:04:05",
+	}
+)
+
+// ParseTimeTarget parses a point in time as accepted by `replay/goto-time`.
+// Relative offsets look like `-5m` or `+1h30m` (a bare delta such as `30s` is
+// treated as a forward offset). Absolute times can be a time of day (`14:32`,
+// `14:32:05`), a local date and time (`2026-10-15 09:00`) or an RFC3339
+// timestamp.
+func ParseTimeTarget(value string) (target TimeTarget, err error) {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return target, fmt.Errorf("time must not be empty")
+	}
+
+	sign := time.Duration(1)
+	delta := value
+	switch value[0] {
+	case '-':
+		sign = -1
+		delta = value[1:]
+	case '+':
+		delta = value[1:]
+	}
+
+	if match := TIME_DELTA_REGEX.FindStringSubmatch(delta); len(delta) > 0 && match != nil {
+		target.IsRelative = true
+		target.Delta = sign * parseTimeDelta(match)
+		return target, nil
+	}
+
+	for _, format := range CLOCK_FORMATS {
+		parsed, err := time.ParseInLocation(format, value, time.Local)
+		if err != nil {
+			continue
+		}
+
+		target.IsClock = true
+		target.Time = parsed
+		return target, nil
+	}
+
+	for _, format := range DATE_FORMATS {
+		parsed, err := time.ParseInLocation(format, value, time.Local)
+		if err != nil {
+			continue
+		}
+
+		target.Time = parsed
+		return target, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		target.Time = parsed
+		return target, nil
+	}
+
+	return target, fmt.Errorf("invalid time: %s", value)
+}
+
+// submitTime jumps to the time in `value`, which the user provided either
+// as the argument to ActionGotoTime or in the time prompt.
+func (r *Replay) submitTime(value string) tea.Cmd {
+	r.isTimeInput = false
+
+	target, err := ParseTimeTarget(value)
+	if err != nil {
+		r.isInvalidTime = true
+		return nil
+	}
+
+	r.exitCopyMode()
+	return r.gotoTime(target)
+}
+
+// gotoTime moves the replay to the point in time described by `target`.
+// Times outside of the recording are clamped to its beginning or end.
+func (r *Replay) gotoTime(target TimeTarget) tea.Cmd {
+	r.isPlaying = false
+	newTime := target.Resolve(r.currentTime)
+	return r.setTimeDelta(newTime.Sub(r.currentTime), false)
+}
+
 func (r *Replay) setTimeDelta(delta time.Duration, skipInactivity bool) tea.Cmd {
 	events := r.Events()
 	if len(events) == 0 {
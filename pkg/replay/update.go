@@ -43,6 +43,12 @@ func (r *Replay) Update(msg tea.Msg) (taro.Model, tea.Cmd) {
 	case seekEvent:
 		r.handleSeek(msg.updateTime)
 		return r, nil
+	case outputEvent:
+		return r, r.handleOutput()
+	case diffEvent:
+		r.diff = msg.lines
+		r.diffOffset = 0
+		return r, nil
 	case ProgressEvent:
 		r.progressPercent = msg.Percent
 		return r, r.waitProgress()
@@ -98,6 +104,10 @@ func (r *Replay) Update(msg tea.Msg) (taro.Model, tea.Cmd) {
 	case ActionEvent:
 		switch msg.Type {
 		case ActionTimePlay:
+			if r.diff != nil {
+				return r, nil
+			}
+
 			r.isPlaying = !r.isPlaying
 
 			if r.isPlaying {
@@ -110,6 +120,7 @@ func (r *Replay) Update(msg tea.Msg) (taro.Model, tea.Cmd) {
 	case taro.KeyMsg:
 		// Clear out the "no matches" dialog
 		r.isEmpty = false
+		r.isInvalidTime = false
 
 		isTime := r.mode == ModeTime
 		return r, func() tea.Msg {
@@ -134,6 +145,21 @@ func (r *Replay) Update(msg tea.Msg) (taro.Model, tea.Cmd) {
 		return r, nil
 	}
 
+	if r.diff != nil {
+		switch msg := msg.(type) {
+		case taro.MouseMsg:
+			switch msg.Button {
+			case taro.MouseWheelUp:
+				r.scrollDiff(-1)
+			case taro.MouseWheelDown:
+				r.scrollDiff(+1)
+			}
+			return r, nil
+		case ActionEvent:
+			return r.handleDiffAction(msg)
+		}
+	}
+
 	switch msg := msg.(type) {
 	case taro.MouseMsg:
 		switch msg.Button {
@@ -159,9 +185,14 @@ func (r *Replay) Update(msg tea.Msg) (taro.Model, tea.Cmd) {
 			if r.isCopyMode() {
 				if r.isSelecting {
 					r.isSelecting = false
-				} else {
-					r.exitCopyMode()
+					return r, nil
+				}
+
+				if r.isFollowing {
+					return r, r.follow()
 				}
+
+				r.exitCopyMode()
 				return r, nil
 			}
 
@@ -182,6 +213,20 @@ func (r *Replay) Update(msg tea.Msg) (taro.Model, tea.Cmd) {
 		case ActionSwapScreen:
 			r.swapScreen()
 			return r, nil
+		case ActionMark:
+			r.setMark()
+			return r, nil
+		case ActionDiff:
+			return r, r.showDiff()
+		case ActionExport:
+			return r, r.export(msg.Arg)
+		case ActionFollow:
+			if r.isFollowing {
+				r.isFollowing = false
+				return r, nil
+			}
+
+			return r, r.follow()
 		case ActionSearchAgain, ActionSearchReverse:
 			if r.isCopyMode() {
 				r.incr.Next(
@@ -209,8 +254,21 @@ func (r *Replay) Update(msg tea.Msg) (taro.Model, tea.Cmd) {
 			}
 
 			r.mode = ModeInput
+			r.isTimeInput = false
 			r.isForward = msg.Type == ActionSearchForward
 			r.searchInput.Reset()
+		case ActionGotoTime:
+			if len(msg.Arg) > 0 {
+				return r, r.submitTime(msg.Arg)
+			}
+
+			if r.isWaiting {
+				return r, nil
+			}
+
+			r.mode = ModeInput
+			r.isTimeInput = true
+			r.searchInput.Reset()
 		case ActionTimeStepBack:
 			return r, r.gotoIndex(r.Location().Index-1, -1)
 		case ActionTimeStepForward:
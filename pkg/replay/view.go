@@ -8,6 +8,7 @@ import (
 	"github.com/cfoust/cy/pkg/geom/image"
 	"github.com/cfoust/cy/pkg/geom/tty"
 	"github.com/cfoust/cy/pkg/replay/detect"
+	"github.com/cfoust/cy/pkg/replay/diff"
 	"github.com/cfoust/cy/pkg/replay/movement"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -96,11 +97,23 @@ func (r *Replay) drawStatusBar(state *tty.State) {
 		statusText = "⏸"
 		statusBG = lipgloss.Color("#7768AE")
 	}
+	if r.isAttached() {
+		statusText = "FOLLOW"
+		statusBG = lipgloss.Color("#F25F5C")
+	}
 
 	if !r.isCopyMode() && r.playbackRate != 1 {
 		statusText += fmt.Sprintf(" %dx", r.playbackRate)
 	}
 
+	if r.mark != nil && !r.isCopyMode() {
+		statusText += " ◆"
+	}
+
+	if r.isFollowing && r.newLines > 0 {
+		statusText += fmt.Sprintf(" +%d", r.newLines)
+	}
+
 	statusStyle := r.render.NewStyle().
 		Inherit(statusBarStyle).
 		Background(statusBG).
@@ -214,6 +227,136 @@ func (r *Replay) drawStatusBar(state *tty.State) {
 	r.render.RenderAt(state.Image, size.R-1, 0, statusBar)
 }
 
+// drawDiff renders the differences between the screen at the mark and the
+// current screen over the entire viewport, either inline or side by side.
+func (r *Replay) drawDiff(state *tty.State) {
+	size := r.viewport
+	state.Image.Clear(geom.Rect{Size: size})
+
+	equalStyle := r.render.NewStyle().
+		Foreground(lipgloss.Color("7"))
+	insertStyle := r.render.NewStyle().
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("#3BB273"))
+	deleteStyle := r.render.NewStyle().
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("#F25F5C"))
+
+	if !r.isDiffSplit {
+		for row := 0; row < size.R; row++ {
+			index := r.diffOffset + row
+			if index >= len(r.diff) {
+				break
+			}
+
+			line := r.diff[index]
+			style := equalStyle
+			prefix := " "
+			switch line.Op {
+			case diff.OpInsert:
+				style = insertStyle
+				prefix = "+"
+			case diff.OpDelete:
+				style = deleteStyle
+				prefix = "-"
+			}
+
+			r.render.RenderAt(
+				state.Image,
+				row, 0,
+				style.Copy().
+					Width(size.C).
+					MaxWidth(size.C).
+					Render(prefix+line.Text),
+			)
+		}
+		return
+	}
+
+	half := geom.Max((size.C-1)/2, 0)
+	rows := diff.Split(r.diff)
+	for row := 0; row < size.R; row++ {
+		index := r.diffOffset + row
+		if index >= len(rows) {
+			break
+		}
+
+		line := rows[index]
+		leftStyle, rightStyle := equalStyle, equalStyle
+		if line.IsChanged {
+			leftStyle, rightStyle = deleteStyle, insertStyle
+		}
+
+		if !line.HasLeft {
+			leftStyle = equalStyle
+		}
+
+		if !line.HasRight {
+			rightStyle = equalStyle
+		}
+
+		r.render.RenderAt(
+			state.Image,
+			row, 0,
+			lipgloss.JoinHorizontal(lipgloss.Top,
+				leftStyle.Copy().
+					Width(half).
+					MaxWidth(half).
+					Render(line.Left),
+				equalStyle.Render("│"),
+				rightStyle.Copy().
+					Width(half).
+					MaxWidth(half).
+					Render(line.Right),
+			),
+		)
+	}
+}
+
+func (r *Replay) drawDiffStatusBar(state *tty.State) {
+	size := state.Image.Size()
+
+	statusBarStyle := r.render.NewStyle().
+		Foreground(lipgloss.Color("15")).
+		Background(lipgloss.Color("8"))
+
+	status := r.render.NewStyle().
+		Inherit(statusBarStyle).
+		Background(lipgloss.Color("#F25F5C")).
+		Padding(0, 1).
+		Render("DIFF")
+
+	var numChanged int
+	for _, line := range r.diff {
+		if line.Op != diff.OpEqual {
+			numChanged++
+		}
+	}
+
+	text := fmt.Sprintf(
+		"%s → %s, %d lines changed",
+		r.Events()[r.mark.Index].Stamp.Format(time.TimeOnly),
+		r.currentTime.Format(time.TimeOnly),
+		numChanged,
+	)
+	if numChanged == 0 {
+		text = "no differences"
+	}
+
+	r.render.RenderAt(
+		state.Image,
+		size.R-1, 0,
+		lipgloss.JoinHorizontal(lipgloss.Left,
+			status,
+			statusBarStyle.
+				Copy().
+				Width(size.C-lipgloss.Width(status)).
+				Padding(0, 1).
+				Render(text),
+		),
+	)
+}
+
 func (r *Replay) renderInput() image.Image {
 	r.searchInput.Cursor.Style = r.render.NewStyle().
 		Background(lipgloss.Color("15"))
@@ -234,7 +377,13 @@ func (r *Replay) renderInput() image.Image {
 	}
 
 	value := r.searchInput.Value()
-	if match := TIME_DELTA_REGEX.FindStringSubmatch(value); len(value) > 0 && match != nil {
+	if r.isTimeInput {
+		promptStyle = common.Copy().
+			Foreground(lipgloss.Color("15")).
+			Background(lipgloss.Color("#7768AE"))
+
+		prompt = "goto-time"
+	} else if match := TIME_DELTA_REGEX.FindStringSubmatch(value); len(value) > 0 && match != nil {
 		promptStyle = common.Copy().
 			Foreground(lipgloss.Color("15")).
 			Background(lipgloss.Color("#7768AE"))
@@ -267,6 +416,8 @@ func (r *Replay) renderInput() image.Image {
 		input = progressStyle.Width(filled).Render("") + inputStyle.Width(width-filled).Render("")
 	} else if r.isEmpty {
 		prompt = "no matches found"
+	} else if r.isInvalidTime {
+		prompt = "invalid time"
 	}
 
 	prompt = promptStyle.Render(prompt)
@@ -375,6 +526,13 @@ func (r *Replay) View(state *tty.State) {
 
 	// Render overlays
 	///////////////////////////
+	if r.diff != nil {
+		state.CursorVisible = false
+		r.drawDiff(state)
+		r.drawDiffStatusBar(state)
+		return
+	}
+
 	r.drawStatusBar(state)
 
 	if r.incr.IsActive() {
@@ -384,7 +542,7 @@ func (r *Replay) View(state *tty.State) {
 
 	// Render text input
 	/////////////////////////////
-	if r.mode != ModeInput && !r.isWaiting && !r.isEmpty {
+	if r.mode != ModeInput && !r.isWaiting && !r.isEmpty && !r.isInvalidTime {
 		return
 	}
 
@@ -3,6 +3,7 @@ package sessions
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	P "github.com/cfoust/cy/pkg/io/protocol"
@@ -17,21 +18,41 @@ func WriteAsciinema(filename string, events []Event) error {
 		return err
 	}
 
+	err = WriteAsciicast(f, events)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// WriteAsciicast writes `events` to `w` in the Asciicast v2 format. The size
+// of the terminal in the header is taken from the first SizeMessage in
+// `events`; timestamps are relative to the first event.
+func WriteAsciicast(w io.Writer, events []Event) error {
+	width, height := 80, 26
+	for _, event := range events {
+		if size, ok := event.Message.(P.SizeMessage); ok {
+			width, height = size.Columns, size.Rows
+			break
+		}
+	}
+
 	// First write the header
 	data, err := json.Marshal(map[string]interface{}{
 		"version": 2,
-		// Theoretically we could derive these, but who cares?
-		"width":  80,
-		"height": 26,
+		"width":   width,
+		"height":  height,
 	})
 	if err != nil {
 		return err
 	}
-	_, err = f.Write(data)
+	_, err = w.Write(data)
 	if err != nil {
 		return err
 	}
-	_, err = f.Write([]byte("\n"))
+	_, err = w.Write([]byte("\n"))
 	if err != nil {
 		return err
 	}
@@ -46,44 +67,37 @@ func WriteAsciinema(filename string, events []Event) error {
 	for _, event := range events {
 		stamp := event.Stamp.Sub(startTime).Seconds()
 
+		var line []interface{}
 		switch event := event.Message.(type) {
 		case P.OutputMessage:
-			data, err := json.Marshal([]interface{}{
+			line = []interface{}{
 				stamp,
 				"o",
 				string(event.Data),
-			})
-			if err != nil {
-				return err
-			}
-			_, err = f.Write(data)
-			if err != nil {
-				return err
 			}
 		case P.SizeMessage:
-			data, err := json.Marshal([]interface{}{
+			line = []interface{}{
 				stamp,
 				"r",
 				fmt.Sprintf("%dx%d", event.Columns, event.Rows),
-			})
-			if err != nil {
-				return err
-			}
-			_, err = f.Write(data)
-			if err != nil {
-				return err
 			}
+		default:
+			continue
 		}
 
-		_, err = f.Write([]byte("\n"))
+		data, err := json.Marshal(line)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write([]byte("\n"))
 		if err != nil {
 			return err
 		}
 	}
 
-	err = f.Close()
-	if err != nil {
-		return err
-	}
 	return nil
 }
@@ -13,6 +13,20 @@ import (
 // EnsureDirectory creates a directory if it does not exist and checks whether
 // other users can read and write files in it.
 func EnsureDirectory(path string) error {
+	return ensureDirectory(path, 0)
+}
+
+// EnsureSharedDirectory is the same as EnsureDirectory, but it also allows
+// other users to traverse the directory (but not list or change its
+// contents) so that they can connect to a socket inside of it.
+func EnsureSharedDirectory(path string) error {
+	return ensureDirectory(path, syscall.S_IXOTH)
+}
+
+// ensureDirectory creates a directory if it does not exist and checks that
+// it belongs to the current user and that other users have no permissions
+// beyond those in `allowed`.
+func ensureDirectory(path string, allowed uint32) error {
 	uid := os.Getuid()
 
 	if err := os.MkdirAll(path, syscall.S_IRWXU); err != nil {
@@ -34,7 +48,7 @@ func EnsureDirectory(path string) error {
 		return err
 	}
 
-	if stat.Uid != uint32(uid) || ((stat.Mode & syscall.S_IRWXO) != 0) {
+	if stat.Uid != uint32(uid) || ((stat.Mode & syscall.S_IRWXO &^ allowed) != 0) {
 		// TODO(cfoust): 09/19/23 this should just be a warning for
 		// recording sessions
 		return fmt.Errorf("%s has unsafe permissions", path)
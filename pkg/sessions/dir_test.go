@@ -0,0 +1,25 @@
+package sessions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sockets")
+	require.NoError(t, EnsureDirectory(dir))
+	require.NoError(t, EnsureSharedDirectory(dir))
+
+	// Other users may traverse a shared directory...
+	require.NoError(t, os.Chmod(dir, 0711))
+	require.Error(t, EnsureDirectory(dir))
+	require.NoError(t, EnsureSharedDirectory(dir))
+
+	// ...but never list its contents
+	require.NoError(t, os.Chmod(dir, 0755))
+	require.Error(t, EnsureDirectory(dir))
+	require.Error(t, EnsureSharedDirectory(dir))
+}
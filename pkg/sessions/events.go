@@ -75,6 +75,11 @@ func (s *EventStream) Resize(size stream.Size) error {
 	return nil
 }
 
+// Handler returns the EventHandler that receives this stream's events.
+func (s *EventStream) Handler() EventHandler {
+	return s.handler
+}
+
 func NewEventStream(stream stream.Stream, handler EventHandler) *EventStream {
 	return &EventStream{
 		stream:  stream,
@@ -110,44 +115,144 @@ func NewMemoryRecorder() *MemoryRecorder {
 
 // A FileRecorder writes incoming events to a file.
 type FileRecorder struct {
-	eventc chan Event
+	mutex    deadlock.Mutex
+	ctx      context.Context
+	filename string
+	eventc   chan Event
+	// stop and done are used to close the current writer, see Close.
+	stop, done chan struct{}
+	closeErr   error
 }
 
 var _ EventHandler = (*FileRecorder)(nil)
 
+// Filename returns the path of the file events are written to.
+func (f *FileRecorder) Filename() string {
+	return f.filename
+}
+
 func (f *FileRecorder) Process(event Event) error {
 	f.eventc <- event
 	return nil
 }
 
-func NewFileRecorder(ctx context.Context, filename string) (*FileRecorder, error) {
-	f := &FileRecorder{
-		eventc: make(chan Event, 100),
+// write writes incoming events with `w` until `stop` is closed, after which
+// it writes any pending events and closes `w`.
+func (f *FileRecorder) write(w SessionWriter, stop, done chan struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case event := <-f.eventc:
+			// TODO(cfoust): 09/19/23 error handling
+			w.Write(event)
+
+			// Flush once there are no more pending events so
+			// that the file can be read while we're still
+			// writing to it
+			if len(f.eventc) == 0 {
+				w.Flush()
+			}
+		case <-stop:
+			for len(f.eventc) > 0 {
+				w.Write(<-f.eventc)
+			}
+
+			err := w.Close()
+			f.mutex.Lock()
+			f.closeErr = err
+			f.mutex.Unlock()
+			return
+		case <-f.ctx.Done():
+			w.Close()
+			return
+		}
+	}
+}
+
+func (f *FileRecorder) start(w SessionWriter) {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	f.stop = stop
+	f.done = done
+	go f.write(w, stop, done)
+}
+
+// Close writes all pending events and closes the file, which can then be
+// appended to by other processes. Events that arrive afterwards are held
+// until Reopen is called.
+func (f *FileRecorder) Close() error {
+	f.mutex.Lock()
+	stop, done := f.stop, f.done
+	f.stop = nil
+	f.mutex.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+
+	close(stop)
+	<-done
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.closeErr
+}
+
+// Reopen resumes writing events to the file after Close.
+func (f *FileRecorder) Reopen() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.stop != nil {
+		return nil
 	}
 
+	w, _, err := Append(f.filename)
+	if err != nil {
+		return err
+	}
+
+	f.start(w)
+	return nil
+}
+
+func NewFileRecorder(ctx context.Context, filename string) (*FileRecorder, error) {
 	w, err := Create(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	f.eventc = make(chan Event, 100)
-
-	go func() {
-		defer w.Close()
-		for {
-			select {
-			case event := <-f.eventc:
-				// TODO(cfoust): 09/19/23 error handling
-				w.Write(event)
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-
+	f := &FileRecorder{
+		ctx:      ctx,
+		filename: filename,
+		eventc:   make(chan Event, 100),
+	}
+	f.start(w)
 	return f, nil
 }
 
+// OpenFileRecorder is the same as NewFileRecorder, but rather than creating
+// a new file, it appends events to an existing one (see Append). It returns
+// the events that were already in the file.
+func OpenFileRecorder(
+	ctx context.Context,
+	filename string,
+) (*FileRecorder, []Event, error) {
+	w, events, err := Append(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f := &FileRecorder{
+		ctx:      ctx,
+		filename: filename,
+		eventc:   make(chan Event, 100),
+	}
+	f.start(w)
+	return f, events, nil
+}
+
 // A MultiplexHandler chains multiple EventHandlers together in sequence, but
 // returns a single EventHandler.
 type MultiplexHandler struct {
@@ -3,6 +3,7 @@ package sessions
 import (
 	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 
 	P "github.com/cfoust/cy/pkg/io/protocol"
@@ -20,6 +21,8 @@ type header struct {
 
 type SessionWriter interface {
 	Write(event Event) error
+	// Flush writes any buffered events to the underlying file.
+	Flush() error
 	Close() error
 }
 
@@ -53,6 +56,10 @@ func (s *sessionWriter) Write(event Event) error {
 	}
 }
 
+func (s *sessionWriter) Flush() error {
+	return s.gz.Flush()
+}
+
 func (s *sessionWriter) Close() error {
 	if err := s.gz.Close(); err != nil {
 		return err
@@ -61,30 +68,68 @@ func (s *sessionWriter) Close() error {
 	return s.file.Close()
 }
 
-func Create(filename string) (SessionWriter, error) {
-	f, err := os.Create(filename)
-	if err != nil {
-		return nil, err
-	}
-
+func newWriter(f *os.File) *sessionWriter {
 	handle := new(codec.MsgpackHandle)
 	gz := gzip.NewWriter(f)
-	encoder := codec.NewEncoder(gz, handle)
-
-	writer := sessionWriter{
+	return &sessionWriter{
 		handle:  handle,
 		gz:      gz,
-		encoder: encoder,
+		encoder: codec.NewEncoder(gz, handle),
 		file:    f,
 	}
+}
+
+func Create(filename string) (SessionWriter, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := encoder.Encode(header{
+	writer := newWriter(f)
+	if err := writer.encoder.Encode(header{
 		Version: SESSION_FILE_VERSION,
 	}); err != nil {
+		f.Close()
 		return nil, err
 	}
 
-	return &writer, nil
+	return writer, nil
+}
+
+// Append opens the .borg file at filename so that new events are written
+// after the ones already in it, which it returns. Each writer produces a
+// separate gzip member, which readers treat as a single stream. If the file
+// was never closed, such as when cy crashed while writing it, it cannot be
+// appended to, so it is instead rewritten with the events that could be
+// read from it.
+func Append(filename string) (SessionWriter, []Event, error) {
+	events, complete, err := readFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !complete {
+		writer, err := Create(filename)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, event := range events {
+			if err := writer.Write(event); err != nil {
+				writer.Close()
+				return nil, nil, err
+			}
+		}
+
+		return writer, events, nil
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return newWriter(f), events, nil
 }
 
 type SessionReader interface {
@@ -135,6 +180,10 @@ func (s *sessionReader) Read() (Event, error) {
 }
 
 func Open(filename string) (SessionReader, error) {
+	return open(filename)
+}
+
+func open(filename string) (*sessionReader, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -166,3 +215,35 @@ func Open(filename string) (SessionReader, error) {
 
 	return &reader, nil
 }
+
+// ReadFile reads all of the events in the .borg file at filename.
+func ReadFile(filename string) ([]Event, error) {
+	events, _, err := readFile(filename)
+	return events, err
+}
+
+// readFile reads all of the events in the .borg file at filename and
+// reports whether the file ended cleanly, which is only true if the writer
+// that created it was closed.
+func readFile(filename string) (events []Event, complete bool, err error) {
+	reader, err := open(filename)
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.file.Close()
+
+	events = make([]Event, 0)
+	for {
+		event, err := reader.Read()
+		if err == io.EOF {
+			return events, true, nil
+		}
+		if err == io.ErrUnexpectedEOF {
+			return events, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		events = append(events, event)
+	}
+}
@@ -1,6 +1,8 @@
 package sessions
 
 import (
+	"bytes"
+
 	P "github.com/cfoust/cy/pkg/io/protocol"
 	"path/filepath"
 	"testing"
@@ -51,3 +53,77 @@ func TestReadWrite(t *testing.T) {
 		require.Equal(t, before, after)
 	}
 }
+
+func TestWriteAsciicast(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteAsciicast(&b, []Event{
+		{
+			Stamp: time.Unix(1, 0),
+			Message: P.SizeMessage{
+				Rows:    24,
+				Columns: 100,
+			},
+		},
+		{
+			Stamp: time.Unix(2, 500000000),
+			Message: P.OutputMessage{
+				Data: []byte("test"),
+			},
+		},
+	}))
+
+	require.Equal(
+		t,
+		`{"height":24,"version":2,"width":100}
+[0,"r","100x24"]
+[1.5,"o","test"]
+`,
+		b.String(),
+	)
+}
+
+func TestAppend(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "foo.borg")
+	output := func(data string) Event {
+		return Event{
+			Stamp:   time.Unix(1, 2).UTC(),
+			Message: P.OutputMessage{Data: []byte(data)},
+		}
+	}
+
+	w, err := Create(name)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(output("first")))
+	require.NoError(t, w.Close())
+
+	w, events, err := Append(name)
+	require.NoError(t, err)
+	require.Equal(t, []Event{output("first")}, events)
+	require.NoError(t, w.Write(output("second")))
+	require.NoError(t, w.Close())
+
+	events, err = ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, []Event{output("first"), output("second")}, events)
+
+	// A file that was never closed is rewritten rather than appended to
+	w, _, err = Append(name)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(output("third")))
+	require.NoError(t, w.Flush())
+
+	w, events, err = Append(name)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.NoError(t, w.Write(output("fourth")))
+	require.NoError(t, w.Close())
+
+	events, err = ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, []Event{
+		output("first"),
+		output("second"),
+		output("third"),
+		output("fourth"),
+	}, events)
+}